@@ -1,9 +1,7 @@
 package main
 
-import (
-	"github.com/beauknowssoftware/go-gql-gen/internal/gengqlinputs"
-)
+import "github.com/beauknowssoftware/go-gql-gen/internal/gqlgen"
 
 func main() {
-	gengqlinputs.Run()
+	gqlgen.Alias("inputs")
 }
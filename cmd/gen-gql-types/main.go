@@ -1,7 +1,7 @@
 package main
 
-import "github.com/beauknowssoftware/go-gql-gen/internal/gengqltypes"
+import "github.com/beauknowssoftware/go-gql-gen/internal/gqlgen"
 
 func main() {
-	gengqltypes.Run()
+	gqlgen.Alias("types")
 }
@@ -1,7 +1,7 @@
 package main
 
-import "github.com/beauknowssoftware/go-gql-gen/internal/genresolvermanifest"
+import "github.com/beauknowssoftware/go-gql-gen/internal/gqlgen"
 
 func main() {
-	genresolvermanifest.Run()
+	gqlgen.Alias("manifest")
 }
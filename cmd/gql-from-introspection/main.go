@@ -0,0 +1,7 @@
+package main
+
+import "github.com/beauknowssoftware/go-gql-gen/internal/gqlfromintrospection"
+
+func main() {
+	gqlfromintrospection.Run()
+}
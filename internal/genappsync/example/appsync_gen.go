@@ -0,0 +1,84 @@
+package example
+
+import (
+	"context"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/appsync"
+)
+
+func Handler(r Resolver) func(ctx context.Context, event appsync.Event) (appsync.Response, error) {
+	return func(ctx context.Context, event appsync.Event) (appsync.Response, error) {
+		return Dispatch(ctx, r, event), nil
+	}
+}
+
+func BatchHandler(r Resolver) func(ctx context.Context, events []appsync.Event) ([]appsync.Response, error) {
+	return func(ctx context.Context, events []appsync.Event) ([]appsync.Response, error) {
+		return DispatchBatch(ctx, r, events), nil
+	}
+}
+
+func Dispatch(ctx context.Context, r Resolver, event appsync.Event) appsync.Response {
+	return DispatchBatch(ctx, r, []appsync.Event{event})[0]
+}
+
+func DispatchBatch(ctx context.Context, r Resolver, events []appsync.Event) []appsync.Response {
+	return appsync.Dispatch(ctx, events, func(ctx context.Context, events []appsync.Event) ([]appsync.Response, bool) {
+		return dispatchBatch(ctx, r, events)
+	}, func(ctx context.Context, event appsync.Event) (interface{}, error) {
+		return dispatch(ctx, r, event)
+	})
+}
+
+func dispatch(ctx context.Context, r Resolver, event appsync.Event) (interface{}, error) {
+	switch event.Info.ParentTypeName + "." + event.Info.FieldName {
+	case "Account.transactions":
+		var parent Account
+		if err := event.DecodeSource(&parent); err != nil {
+			return nil, err
+		}
+		var args AccountTransactionsArgs
+		if err := event.DecodeArguments(&args); err != nil {
+			return nil, err
+		}
+		if err := args.Validate(); err != nil {
+			return nil, err
+		}
+		return r.Account().Transactions(ctx, &parent, args)
+	case "Query.account":
+		var args QueryAccountArgs
+		if err := event.DecodeArguments(&args); err != nil {
+			return nil, err
+		}
+		if err := args.Validate(); err != nil {
+			return nil, err
+		}
+		return r.Query().Account(ctx, args)
+	case "Mutation.openAccount":
+		var args MutationOpenAccountArgs
+		if err := event.DecodeArguments(&args); err != nil {
+			return nil, err
+		}
+		if err := args.Validate(); err != nil {
+			return nil, err
+		}
+		return r.Mutation().OpenAccount(ctx, args)
+	}
+	return nil, appsync.UnknownField(event.Info)
+}
+
+func dispatchBatch(ctx context.Context, r Resolver, events []appsync.Event) ([]appsync.Response, bool) {
+	switch events[0].Info.ParentTypeName + "." + events[0].Info.FieldName {
+	case "Account.owner":
+		parents := make([]*Account, len(events))
+		for i := range parents {
+			parents[i] = new(Account)
+		}
+		return appsync.Batch(events, func(i int, e appsync.Event) error {
+			return e.DecodeSource(parents[i])
+		}, func(items []int) ([]*User, []error) {
+			return r.Account().Owner(ctx, appsync.Pick(parents, items))
+		}), true
+	}
+	return nil, false
+}
@@ -0,0 +1,5 @@
+// Package example is an AppSync Lambda resolver built on the code gqlgen
+// generates. Its tests replay recorded events.
+package example
+
+//go:generate gqlgen generate
@@ -0,0 +1,157 @@
+package example_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/beauknowssoftware/go-gql-gen/internal/genappsync/example"
+	"github.com/beauknowssoftware/go-gql-gen/pkg/appsync"
+)
+
+type resolver struct{}
+
+func (resolver) Account() example.AccountResolver   { return accountResolver{} }
+func (resolver) Query() example.QueryResolver       { return queryResolver{} }
+func (resolver) Mutation() example.MutationResolver { return mutationResolver{} }
+
+type accountResolver struct{}
+
+func (accountResolver) Owner(ctx context.Context, parents []*example.Account) ([]*example.User, []error) {
+	event, _ := appsync.EventFrom(ctx)
+	name := "anonymous"
+	if event.Identity != nil {
+		name = event.Identity.Username
+	}
+	users := make([]*example.User, len(parents))
+	errs := make([]error, len(parents))
+	for i, parent := range parents {
+		if parent.OwnerID == "" {
+			errs[i] = appsync.Errorf("NotFound", "account %v has no owner", parent.ID)
+			continue
+		}
+		users[i] = &example.User{ID: parent.OwnerID, Name: name}
+	}
+	return users, errs
+}
+
+func (accountResolver) Transactions(ctx context.Context, parent *example.Account, args example.AccountTransactionsArgs) ([]example.Transaction, error) {
+	ts := make([]example.Transaction, args.First)
+	for i := range ts {
+		ts[i] = example.Transaction{ID: example.ID(string(parent.ID) + "-" + string(rune('a'+i))), Amount: 10 * (i + 1)}
+	}
+	return ts, nil
+}
+
+type queryResolver struct{}
+
+func (queryResolver) Account(ctx context.Context, args example.QueryAccountArgs) (*example.Account, error) {
+	if args.ID == "missing" {
+		return nil, appsync.Errorf("NotFound", "account %v does not exist", args.ID)
+	}
+	return &example.Account{ID: args.ID, OwnerID: "u1"}, nil
+}
+
+type mutationResolver struct{}
+
+func (mutationResolver) OpenAccount(ctx context.Context, args example.MutationOpenAccountArgs) (example.Account, error) {
+	return example.Account{ID: "a2", OwnerID: args.Input.Owner.ID}, nil
+}
+
+func TestDispatch(t *testing.T) {
+	events, err := filepath.Glob("testdata/events/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, filename := range events {
+		name := strings.TrimSuffix(filepath.Base(filename), ".json")
+		t.Run(name, func(t *testing.T) {
+			d, err := ioutil.ReadFile(filename)
+			if err != nil {
+				t.Fatalf("failed to read event %v", err)
+			}
+			// AppSync sends an array of events when it batches a field.
+			var response interface{}
+			if strings.HasPrefix(strings.TrimSpace(string(d)), "[") {
+				var events []appsync.Event
+				if err := json.Unmarshal(d, &events); err != nil {
+					t.Fatalf("failed to parse events %v", err)
+				}
+				response = example.DispatchBatch(context.Background(), resolver{}, events)
+			} else {
+				var event appsync.Event
+				if err := json.Unmarshal(d, &event); err != nil {
+					t.Fatalf("failed to parse event %v", err)
+				}
+				response = example.Dispatch(context.Background(), resolver{}, event)
+			}
+
+			got, err := json.MarshalIndent(response, "", "  ")
+			if err != nil {
+				t.Fatalf("failed to marshal response %v", err)
+			}
+			expected, err := ioutil.ReadFile(filepath.Join("testdata/responses", name+".json"))
+			if err != nil {
+				t.Fatalf("failed to read response %v", err)
+			}
+			if diff := cmp.Diff(strings.TrimSpace(string(expected)), string(got)); diff != "" {
+				t.Fatalf("mismatch (-expected,+got) %v", diff)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	// Structs built in Go are taken to hold every required value.
+	args := example.MutationOpenAccountArgs{Input: example.AccountInput{Tags: []string{}}}
+	if err := args.Validate(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := map[string]string{
+		`{"input": {"owner": {"id": "u7"}, "tags": []}}`: "",
+		`{"input": {"owner": {}, "tags": []}}`:           "input.owner.id: required value is missing",
+		`{"input": {"owner": null, "tags": []}}`:         "input.owner: required value is missing",
+		`{}`:                                             "input: required value is missing",
+	}
+	for d, expected := range tests {
+		var args example.MutationOpenAccountArgs
+		if err := json.Unmarshal([]byte(d), &args); err != nil {
+			t.Fatalf("failed to decode %v: %v", d, err)
+		}
+		got := ""
+		if err := args.Validate(); err != nil {
+			got = err.Error()
+		}
+		if got != expected {
+			t.Fatalf("expected %q for %v, got %q", expected, d, got)
+		}
+	}
+}
+
+// TestComparable only compiles while generated inputs with required fields
+// stay comparable.
+func TestComparable(t *testing.T) {
+	var a, b example.AccountTransactionsArgs
+	if err := json.Unmarshal([]byte(`{"first": 10}`), &a); err != nil {
+		t.Fatalf("failed to unmarshal %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"first": 10, "after": null}`), &b); err != nil {
+		t.Fatalf("failed to unmarshal %v", err)
+	}
+	if a != b {
+		t.Fatalf("expected %+v to equal %+v", a, b)
+	}
+	if a == (example.AccountTransactionsArgs{First: 10}) {
+		t.Fatalf("expected decoded args to differ from args built in Go")
+	}
+	seen := map[example.OwnerInput]bool{{ID: "1"}: true}
+	if !seen[example.OwnerInput{ID: "1"}] {
+		t.Fatalf("expected inputs to work as map keys")
+	}
+}
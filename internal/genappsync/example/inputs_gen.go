@@ -0,0 +1,160 @@
+package example
+
+import (
+	"github.com/beauknowssoftware/go-gql-gen/pkg/gql"
+)
+
+type ID string
+
+type OwnerInput struct {
+	ID     ID `json:"id"`
+	fields gql.Fields
+}
+
+func (v OwnerInput) Validate() error {
+	var errs gql.Errors
+	v.validate("", &errs)
+	return errs.Err()
+}
+
+func (v OwnerInput) validate(path string, errs *gql.Errors) {
+	if v.fields.Missing("id") {
+		errs.Missing(gql.Path(path, "id"))
+	}
+}
+
+func (v *OwnerInput) UnmarshalJSON(d []byte) error {
+	type plain OwnerInput
+	var err error
+	v.fields, err = gql.Decode(d, "OwnerInput", (*plain)(v), "id")
+	return err
+}
+
+type AccountInput struct {
+	Owner  OwnerInput `json:"owner"`
+	Tags   []string   `json:"tags"`
+	fields gql.Fields
+}
+
+func (v AccountInput) Validate() error {
+	var errs gql.Errors
+	v.validate("", &errs)
+	return errs.Err()
+}
+
+func (v AccountInput) validate(path string, errs *gql.Errors) {
+	if v.fields.Missing("owner") {
+		errs.Missing(gql.Path(path, "owner"))
+	} else {
+		v.Owner.validate(gql.Path(path, "owner"), errs)
+	}
+	if v.Tags == nil {
+		errs.Missing(gql.Path(path, "tags"))
+	}
+}
+
+func (v *AccountInput) UnmarshalJSON(d []byte) error {
+	type plain AccountInput
+	var err error
+	v.fields, err = gql.Decode(d, "AccountInput", (*plain)(v), "owner")
+	return err
+}
+
+type Account struct {
+	ID      ID `json:"id"`
+	OwnerID ID `json:"ownerId"`
+	AccountOwnerLink
+	AccountTransactionsLink
+}
+
+type AccountOwnerLink struct {
+	// ownerId is read from Account.OwnerID.
+}
+
+type AccountTransactionsLink struct {
+}
+
+type User struct {
+	ID   ID     `json:"id"`
+	Name string `json:"name"`
+}
+
+type Transaction struct {
+	ID     ID  `json:"id"`
+	Amount int `json:"amount"`
+}
+
+type AccountTransactionsArgs struct {
+	First  int     `json:"first"`
+	After  *string `json:"after"`
+	fields gql.Fields
+}
+
+func (v AccountTransactionsArgs) Validate() error {
+	var errs gql.Errors
+	v.validate("", &errs)
+	return errs.Err()
+}
+
+func (v AccountTransactionsArgs) validate(path string, errs *gql.Errors) {
+	if v.fields.Missing("first") {
+		errs.Missing(gql.Path(path, "first"))
+	}
+}
+
+func (v *AccountTransactionsArgs) UnmarshalJSON(d []byte) error {
+	type plain AccountTransactionsArgs
+	var err error
+	v.fields, err = gql.Decode(d, "AccountTransactionsArgs", (*plain)(v), "first")
+	return err
+}
+
+type QueryAccountArgs struct {
+	ID     ID `json:"id"`
+	fields gql.Fields
+}
+
+func (v QueryAccountArgs) Validate() error {
+	var errs gql.Errors
+	v.validate("", &errs)
+	return errs.Err()
+}
+
+func (v QueryAccountArgs) validate(path string, errs *gql.Errors) {
+	if v.fields.Missing("id") {
+		errs.Missing(gql.Path(path, "id"))
+	}
+}
+
+func (v *QueryAccountArgs) UnmarshalJSON(d []byte) error {
+	type plain QueryAccountArgs
+	var err error
+	v.fields, err = gql.Decode(d, "QueryAccountArgs", (*plain)(v), "id")
+	return err
+}
+
+type MutationOpenAccountArgs struct {
+	Input  AccountInput `json:"input"`
+	fields gql.Fields
+}
+
+func (v MutationOpenAccountArgs) Validate() error {
+	var errs gql.Errors
+	v.validate("", &errs)
+	return errs.Err()
+}
+
+func (v MutationOpenAccountArgs) validate(path string, errs *gql.Errors) {
+	if v.fields.Missing("input") {
+		errs.Missing(gql.Path(path, "input"))
+	} else {
+		v.Input.validate(gql.Path(path, "input"), errs)
+	}
+}
+
+func (v *MutationOpenAccountArgs) UnmarshalJSON(d []byte) error {
+	type plain MutationOpenAccountArgs
+	var err error
+	v.fields, err = gql.Decode(d, "MutationOpenAccountArgs", (*plain)(v), "input")
+	return err
+}
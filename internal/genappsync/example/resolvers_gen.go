@@ -0,0 +1,24 @@
+package example
+
+import (
+	"context"
+)
+
+type AccountResolver interface {
+	Owner(ctx context.Context, parents []*Account) ([]*User, []error)
+	Transactions(ctx context.Context, parent *Account, args AccountTransactionsArgs) ([]Transaction, error)
+}
+
+type QueryResolver interface {
+	Account(ctx context.Context, args QueryAccountArgs) (*Account, error)
+}
+
+type MutationResolver interface {
+	OpenAccount(ctx context.Context, args MutationOpenAccountArgs) (Account, error)
+}
+
+type Resolver interface {
+	Account() AccountResolver
+	Query() QueryResolver
+	Mutation() MutationResolver
+}
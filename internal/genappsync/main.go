@@ -0,0 +1,135 @@
+package genappsync
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/beauknowssoftware/go-gql-gen/internal/gogen"
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+const appsyncPackage = "github.com/beauknowssoftware/go-gql-gen/pkg/appsync"
+
+type field struct {
+	tdn parse.TypeDefNode
+	fn  parse.FieldNode
+}
+
+// Generate writes the AppSync direct Lambda dispatcher for the @resolve
+// fields of the schema in package pkg to w. Dispatch decodes the source
+// and arguments of an event, validates the arguments and calls the method
+// of the Resolver written by genresolvers, which is expected in the same
+// package. DispatchBatch does the same for the arrays AppSync sends when
+// it batches a field, calling the methods of @resolve(batch: true) fields
+// once per batch. Handler and BatchHandler wrap them for lambda.Start.
+// Both return appsync.Response values: AppSync unwraps those of batched
+// fields itself, while single fields need the response mapping template
+// that the cloudformation, sam and terraform manifest formats declare.
+func Generate(w io.Writer, rnode parse.Node, pkg string, o gogen.Options) error {
+	f := gogen.NewFile(pkg, rnode, o)
+	var fields, batchFields []field
+	parse.Traverse(rnode, func(n parse.Node) bool {
+		tdn, ok := n.(parse.TypeDefNode)
+		if !ok {
+			return true
+		}
+		if tdn.Input {
+			return false
+		}
+		for _, n := range tdn.Fields {
+			fn := n.(parse.FieldNode)
+			switch {
+			case !gogen.Resolved(fn):
+			case gogen.Batch(fn):
+				batchFields = append(batchFields, field{tdn, fn})
+			default:
+				fields = append(fields, field{tdn, fn})
+			}
+		}
+		return false
+	})
+
+	ctx := f.Import("context.Context")
+	event := f.Import(appsyncPackage + ".Event")
+	response := f.Import(appsyncPackage + ".Response")
+	for _, name := range []string{"Handler", "BatchHandler", "Dispatch", "DispatchBatch", "dispatch", "dispatchBatch"} {
+		f.Declare(name, "dispatcher")
+	}
+	fmt.Fprintf(f, "\nfunc Handler(r Resolver) func(ctx %v, event %v) (%v, error) {\n", ctx, event, response)
+	fmt.Fprintf(f, "\treturn func(ctx %v, event %v) (%v, error) {\n\t\treturn Dispatch(ctx, r, event), nil\n\t}\n}\n", ctx, event, response)
+	fmt.Fprintf(f, "\nfunc BatchHandler(r Resolver) func(ctx %v, events []%v) ([]%v, error) {\n", ctx, event, response)
+	fmt.Fprintf(f, "\treturn func(ctx %v, events []%v) ([]%v, error) {\n\t\treturn DispatchBatch(ctx, r, events), nil\n\t}\n}\n", ctx, event, response)
+	fmt.Fprintf(f, "\nfunc Dispatch(ctx %v, r Resolver, event %v) %v {\n", ctx, event, response)
+	fmt.Fprintf(f, "\treturn DispatchBatch(ctx, r, []%v{event})[0]\n}\n", event)
+	fmt.Fprintf(f, "\nfunc DispatchBatch(ctx %v, r Resolver, events []%v) []%v {\n", ctx, event, response)
+	fmt.Fprintf(f, "\treturn %v(ctx, events, func(ctx %v, events []%v) ([]%v, bool) {\n", f.Import(appsyncPackage+".Dispatch"), ctx, event, response)
+	fmt.Fprintf(f, "\t\treturn dispatchBatch(ctx, r, events)\n\t}, func(ctx %v, event %v) (interface{}, error) {\n", ctx, event)
+	fmt.Fprintf(f, "\t\treturn dispatch(ctx, r, event)\n\t})\n}\n")
+
+	fmt.Fprintf(f, "\nfunc dispatch(ctx %v, r Resolver, event %v) (interface{}, error) {\n", ctx, event)
+	if len(fields) > 0 {
+		fmt.Fprintln(f, "\tswitch event.Info.ParentTypeName + \".\" + event.Info.FieldName {")
+		for _, fd := range fields {
+			tdn, fn := fd.tdn, fd.fn
+			fmt.Fprintf(f, "\tcase %q:\n", tdn.Name+"."+fn.Name)
+			call := []string{"ctx"}
+			if f.Operation(tdn.Name) == "" {
+				fmt.Fprintf(f, "\t\tvar parent %v\n", f.Named(tdn.Name))
+				fmt.Fprintln(f, "\t\tif err := event.DecodeSource(&parent); err != nil {\n\t\t\treturn nil, err\n\t\t}")
+				call = append(call, "&parent")
+			}
+			if len(fn.Params) > 0 {
+				fmt.Fprintf(f, "\t\tvar args %v\n", f.ArgsName(tdn.Name, fn.Name))
+				fmt.Fprintln(f, "\t\tif err := event.DecodeArguments(&args); err != nil {\n\t\t\treturn nil, err\n\t\t}")
+				fmt.Fprintln(f, "\t\tif err := args.Validate(); err != nil {\n\t\t\treturn nil, err\n\t\t}")
+				call = append(call, "args")
+			}
+			fmt.Fprintf(f, "\t\treturn r.%v().%v(%v)\n", f.TypeName(tdn.Name), f.FieldName(tdn.Name, fn.Name), strings.Join(call, ", "))
+		}
+		fmt.Fprintln(f, "\t}")
+	}
+	fmt.Fprintf(f, "\treturn nil, %v(event.Info)\n}\n", f.Import(appsyncPackage+".UnknownField"))
+
+	fmt.Fprintf(f, "\nfunc dispatchBatch(ctx %v, r Resolver, events []%v) ([]%v, bool) {\n", ctx, event, response)
+	if len(batchFields) > 0 {
+		fmt.Fprintln(f, "\tswitch events[0].Info.ParentTypeName + \".\" + events[0].Info.FieldName {")
+		for _, fd := range batchFields {
+			tdn, fn := fd.tdn, fd.fn
+			fmt.Fprintf(f, "\tcase %q:\n", tdn.Name+"."+fn.Name)
+			call := []string{"ctx"}
+			var steps []string
+			if f.Operation(tdn.Name) == "" {
+				fmt.Fprintf(f, "\t\tparents := make([]*%v, len(events))\n", f.Named(tdn.Name))
+				fmt.Fprintf(f, "\t\tfor i := range parents {\n\t\t\tparents[i] = new(%v)\n\t\t}\n", f.Named(tdn.Name))
+				steps = append(steps, "e.DecodeSource(parents[i])")
+				call = append(call, f.Import(appsyncPackage+".Pick")+"(parents, items)")
+			}
+			if len(fn.Params) > 0 {
+				fmt.Fprintf(f, "\t\targs := make([]%v, len(events))\n", f.ArgsName(tdn.Name, fn.Name))
+				steps = append(steps, "e.DecodeArguments(&args[i])", "args[i].Validate()")
+				call = append(call, f.Import(appsyncPackage+".Pick")+"(args, items)")
+			}
+			var decode strings.Builder
+			for i, step := range steps {
+				if i == len(steps)-1 {
+					fmt.Fprintf(&decode, "\t\t\treturn %v\n", step)
+				} else {
+					fmt.Fprintf(&decode, "\t\t\tif err := %v; err != nil {\n\t\t\t\treturn err\n\t\t\t}\n", step)
+				}
+			}
+			if len(steps) == 0 {
+				decode.WriteString("\t\t\treturn nil\n")
+			}
+			fmt.Fprintf(f, "\t\treturn %v(events, func(i int, e %v) error {\n%v", f.Import(appsyncPackage+".Batch"), event, decode.String())
+			t := f.Type(tdn.Name, fn.Name, fn.Type.(parse.TypeNode))
+			fmt.Fprintf(f, "\t\t}, func(items []int) ([]%v, []error) {\n", t)
+			fmt.Fprintf(f, "\t\t\treturn r.%v().%v(%v)\n", f.TypeName(tdn.Name), f.FieldName(tdn.Name, fn.Name), strings.Join(call, ", "))
+			fmt.Fprintln(f, "\t\t}), true")
+		}
+		fmt.Fprintln(f, "\t}")
+	}
+	fmt.Fprintln(f, "\treturn nil, false\n}")
+	_, err := f.WriteTo(w)
+	return err
+}
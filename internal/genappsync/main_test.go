@@ -0,0 +1,51 @@
+package genappsync_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func ReadFile(t *testing.T, filename string) string {
+	d, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read file %v", filename)
+	}
+	return string(d)
+}
+
+// Test_Example checks that the generated code of the example package is up
+// to date, so that its tests cover what gqlgen generates.
+func Test_Example(t *testing.T) {
+	dir, err := ioutil.TempDir("", "genappsync")
+	if err != nil {
+		t.Fatalf("failed to create temp dir %v", err)
+	}
+	defer os.RemoveAll(dir)
+	for _, f := range []string{"gqlgen.json", "schema.graphqls"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, f), []byte(ReadFile(t, "example/"+f)), 0644); err != nil {
+			t.Fatalf("failed to write file %v", err)
+		}
+	}
+
+	cmd := exec.Command("gqlgen", "generate")
+	cmd.Dir = dir
+	var errBuff bytes.Buffer
+	cmd.Stderr = &errBuff
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("failed to run %v\n%v", err, errBuff.String())
+	}
+
+	for _, f := range []string{"inputs_gen.go", "resolvers_gen.go", "appsync_gen.go"} {
+		t.Run(f, func(t *testing.T) {
+			if diff := cmp.Diff(ReadFile(t, "example/"+f), ReadFile(t, filepath.Join(dir, f))); diff != "" {
+				t.Fatalf("mismatch (-expected,+got) %v, run go generate in example", diff)
+			}
+		})
+	}
+}
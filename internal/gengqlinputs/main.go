@@ -1,95 +1,51 @@
 package gengqlinputs
 
 import (
-	"flag"
 	"fmt"
-	"io/ioutil"
-	"os"
-	"strings"
+	"io"
 
+	"github.com/beauknowssoftware/go-gql-gen/internal/gogen"
 	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
 )
 
-var packageFlag = flag.String("package", "", "")
-
-func hasResolveDirective(fn parse.FieldNode) bool {
-	for _, n := range fn.Directives {
-		if dn, ok := n.(parse.DirectiveNode); ok && dn.Name == "resolve" {
-			return true
-		}
-	}
-	return false
-}
-
-func Run() {
-	flag.Parse()
-
-	pkg, hasPkgEnv := os.LookupEnv("GOPACKAGE")
-	if !hasPkgEnv && *packageFlag == "" {
-		fmt.Fprint(os.Stderr, "either GOPACKAGE environment variable or package flag must be set\n")
-		os.Exit(1)
-	} else if !hasPkgEnv {
-		pkg = *packageFlag
-	}
-
-	schemaBytes, err := ioutil.ReadAll(os.Stdin)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to read schema from stdin: %v\n", err)
-		os.Exit(1)
-	}
-	schema := string(schemaBytes)
-
-	p := parse.New(parse.NewLexer(schema))
-	rnode, perr := p.Parse()
-	if perr != nil {
-		fmt.Fprintf(os.Stderr, "failed to parse schema: %v (%v)\n", perr.Error, perr.Token)
-		os.Exit(1)
+// Generate writes the Go input, object and argument types for the schema in
+// package pkg to w.
+func Generate(w io.Writer, rnode parse.Node, pkg string, o gogen.Options) error {
+	f := gogen.NewFile(pkg, rnode, o)
+	if id, _ := f.Scalar("ID"); id == "ID" {
+		f.Declare("ID", "scalar ID")
+		fmt.Fprintln(f, "type ID string")
 	}
-
-	fmt.Printf("package %v\n\n", pkg)
-	fmt.Println("type ID string")
-	fmt.Println()
 	parse.Traverse(rnode, func(n parse.Node) bool {
 		if tdn, ok := n.(parse.TypeDefNode); ok {
-			if !tdn.Input {
+			if !tdn.Input || f.External(tdn.Name) {
 				return false
 			}
-			fmt.Printf("type %v struct {\n", tdn.Name)
+			fmt.Fprintln(f)
+			s := f.Struct(f.TypeName(tdn.Name), tdn.Name, "type "+tdn.Name)
+			v := f.Validation()
+			fmt.Fprintf(f, "type %v struct {\n", f.TypeName(tdn.Name))
 			parse.Traverse(tdn, func(n parse.Node) bool {
 				if fn, ok := n.(parse.FieldNode); ok {
 					tn := fn.Type.(parse.TypeNode)
 					if tn.Name == "Query" {
 						return false
 					}
-					if strings.HasSuffix(fn.Name, "Id") {
-						prefix := strings.TrimSuffix(fn.Name, "Id")
-						fmt.Printf("\t%vID", strings.Title(prefix))
-					} else if fn.Name == "id" {
-						fmt.Print("\tID")
-					} else {
-						fmt.Printf("\t%v", strings.Title(fn.Name))
-					}
-					switch tn.Name {
-					case "String":
-						fmt.Print(" string")
-					case "Int":
-						fmt.Print(" int")
-					default:
-						if tn.Multiple {
-							fmt.Printf(" []%v", tn.Name)
-						} else if tn.Name == "ID" {
-							fmt.Print(" ID")
-						} else {
-							fmt.Printf(" *%v", tn.Name)
-						}
-					}
-					fmt.Printf(" `json:\"%v\"`", fn.Name)
-					fmt.Println()
+					name, t := s.Field(fn.Name), f.InputType(tdn.Name, fn.Name, tn)
+					v.Field(name, fn.Name, t, tn)
+					fmt.Fprintf(f, "\t%v", name)
+					fmt.Fprintf(f, " %v", t)
+					fmt.Fprintf(f, " %v", f.InputTag(fn.Name, t))
+					fmt.Fprintln(f)
 					return false
 				}
 				return true
 			})
-			fmt.Println("}")
+			v.Fields(s)
+			fmt.Fprintln(f, "}")
+			s.Method("Validate")
+			s.Method("UnmarshalJSON")
+			v.Methods(f.TypeName(tdn.Name))
 			return false
 		}
 		return true
@@ -97,58 +53,49 @@ func Run() {
 
 	parse.Traverse(rnode, func(n parse.Node) bool {
 		if tdn, ok := n.(parse.TypeDefNode); ok {
-			if tdn.Input || tdn.Name == "Mutation" || tdn.Name == "Query" {
+			if tdn.Input || tdn.Name == "Mutation" || tdn.Name == "Query" || f.External(tdn.Name) {
 				return false
 			}
-			fmt.Println()
-			fmt.Printf("type %v struct {\n", tdn.Name)
+			fmt.Fprintln(f)
+			s := f.Struct(f.TypeName(tdn.Name), tdn.Name, "type "+tdn.Name)
+			var links []parse.FieldNode
+			fmt.Fprintf(f, "type %v struct {\n", f.TypeName(tdn.Name))
 			parse.Traverse(tdn, func(n parse.Node) bool {
 				if fn, ok := n.(parse.FieldNode); ok {
 					tn := fn.Type.(parse.TypeNode)
 					if tn.Name == "Query" {
 						return false
 					}
-					if hasResolveDirective(fn) {
-						fmt.Printf("\t%v%vLink\n", tdn.Name, strings.Title(fn.Name))
+					if gogen.Resolved(fn) {
+						link := f.TypeName(tdn.Name) + f.FieldName(tdn.Name, fn.Name) + "Link"
+						s.Embed(link)
+						links = append(links, fn)
+						fmt.Fprintf(f, "\t%v\n", link)
 						return false
 					}
-					if strings.HasSuffix(fn.Name, "Id") {
-						prefix := strings.TrimSuffix(fn.Name, "Id")
-						fmt.Printf("\t%vID", strings.Title(prefix))
-					} else if fn.Name == "id" {
-						fmt.Print("\tID")
-					} else {
-						fmt.Printf("\t%v", strings.Title(fn.Name))
-					}
-					switch tn.Name {
-					case "String":
-						if tn.Multiple {
-							fmt.Print(" []string")
-						} else {
-							fmt.Print(" string")
-						}
-					case "Int":
-						if tn.Multiple {
-							fmt.Print(" []int")
-						} else {
-							fmt.Print(" int")
-						}
-					case "ID":
-						fmt.Print(" ID")
-					default:
-						if tn.Multiple {
-							fmt.Printf(" []%v", tn.Name)
-						} else {
-							fmt.Printf(" %v", tn.Name)
-						}
-					}
-					fmt.Printf(" `json:\"%v\"`", fn.Name)
-					fmt.Println()
+					fmt.Fprintf(f, "\t%v", s.Field(fn.Name))
+					fmt.Fprintf(f, " %v", f.Type(tdn.Name, fn.Name, tn))
+					fmt.Fprintf(f, " `json:\"%v\"`", fn.Name)
+					fmt.Fprintln(f)
 					return false
 				}
 				return true
 			})
-			fmt.Println("}")
+			fmt.Fprintln(f, "}")
+
+			for _, fn := range links {
+				link := f.TypeName(tdn.Name) + f.FieldName(tdn.Name, fn.Name) + "Link"
+				f.Declare(link, "link of "+tdn.Name+"."+fn.Name)
+				fmt.Fprintf(f, "\ntype %v struct {\n", link)
+				for _, k := range f.LinkKeys(tdn, fn) {
+					if k.Sibling {
+						fmt.Fprintf(f, "\t// %v is read from %v.%v.\n", k.Name, f.TypeName(tdn.Name), k.GoName)
+						continue
+					}
+					fmt.Fprintf(f, "\t%v %v %v\n", k.GoName, k.Type, k.Tag())
+				}
+				fmt.Fprintln(f, "}")
+			}
 			return false
 		}
 		return true
@@ -165,45 +112,26 @@ func Run() {
 						return false
 					}
 
-					fmt.Println()
-					fmt.Printf("type %v%vArgs struct {\n", tdn.Name, strings.Title(fn.Name))
+					fmt.Fprintln(f)
+					name := f.ArgsName(tdn.Name, fn.Name)
+					s := f.Struct(name, tdn.Name, "arguments of "+tdn.Name+"."+fn.Name)
+					v := f.Validation()
+					fmt.Fprintf(f, "type %v struct {\n", name)
 					for _, n := range fn.Params {
 						pn := n.(parse.ParamNode)
 						tn := pn.Type.(parse.TypeNode)
-						if strings.HasSuffix(pn.Name, "Id") {
-							prefix := strings.TrimSuffix(pn.Name, "Id")
-							fmt.Printf("\t%vID", strings.Title(prefix))
-						} else if pn.Name == "id" {
-							fmt.Print("\tID")
-						} else {
-							fmt.Printf("\t%v", strings.Title(pn.Name))
-						}
-						switch tn.Name {
-						case "String":
-							if tn.Multiple {
-								fmt.Print(" []string")
-							} else {
-								fmt.Print(" string")
-							}
-						case "Int":
-							if tn.Multiple {
-								fmt.Print(" []int")
-							} else {
-								fmt.Print(" int")
-							}
-						case "ID":
-							fmt.Print(" ID")
-						default:
-							if tn.Multiple {
-								fmt.Printf(" []%v", tn.Name)
-							} else {
-								fmt.Printf(" %v", tn.Name)
-							}
-						}
-						fmt.Printf(" `json:\"%v\"`", pn.Name)
-						fmt.Println()
+						field, t := s.Field(fn.Name+"."+pn.Name), f.InputType(tdn.Name, fn.Name+"."+pn.Name, tn)
+						v.Field(field, pn.Name, t, tn)
+						fmt.Fprintf(f, "\t%v", field)
+						fmt.Fprintf(f, " %v", t)
+						fmt.Fprintf(f, " %v", f.InputTag(pn.Name, t))
+						fmt.Fprintln(f)
 					}
-					fmt.Println("}")
+					v.Fields(s)
+					fmt.Fprintln(f, "}")
+					s.Method("Validate")
+					s.Method("UnmarshalJSON")
+					v.Methods(name)
 				}
 				return true
 			})
@@ -211,4 +139,6 @@ func Run() {
 		}
 		return true
 	})
+	_, err := f.WriteTo(w)
+	return err
 }
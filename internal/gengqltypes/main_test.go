@@ -52,3 +52,19 @@ func Test_Main(t *testing.T) {
 		})
 	}
 }
+
+func Test_Config(t *testing.T) {
+	cmd := exec.Command("gen-gql-types", "-config", "testdata/gqlgen.json")
+	var outBuff, errBuff bytes.Buffer
+	cmd.Stdout = &outBuff
+	cmd.Stderr = &errBuff
+
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("failed to run %v\n%v\n%v", err, outBuff.String(), errBuff.String())
+	}
+
+	expected := ReadFile(t, "testdata/config.go.test")
+	if diff := cmp.Diff(expected, outBuff.String()); diff != "" {
+		t.Fatalf("mismatch (-expected,+got) %v", diff)
+	}
+}
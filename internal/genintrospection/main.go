@@ -0,0 +1,53 @@
+package genintrospection
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/introspection"
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+var (
+	bareFlag       = flag.Bool("bare", false, "omit the {\"data\": ...} response envelope")
+	awsScalarsFlag = flag.Bool("aws-scalars", false, "declare the AppSync AWS* scalars the schema uses without defining")
+)
+
+func Run() {
+	flag.Parse()
+
+	schemaBytes, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read schema from stdin: %v\n", err)
+		os.Exit(1)
+	}
+	schema := string(schemaBytes)
+
+	p := parse.New(parse.NewLexer(schema))
+	rnode, perr := p.Parse()
+	if perr != nil {
+		fmt.Fprintf(os.Stderr, "failed to parse schema: %v (%v)\n", perr.Error, perr.Token)
+		os.Exit(1)
+	}
+
+	s, err := introspection.FromDocument(rnode, introspection.Options{AWSScalars: *awsScalarsFlag})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to build introspection result: %v\n", err)
+		os.Exit(1)
+	}
+
+	var result interface{} = introspection.Response{Data: &introspection.Data{Schema: s}}
+	if *bareFlag {
+		result = introspection.Data{Schema: s}
+	}
+
+	d, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to marshal introspection result: %v\n", err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(d)
+}
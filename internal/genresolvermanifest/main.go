@@ -3,61 +3,198 @@ package genresolvermanifest
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"os"
+	"io"
 
+	"github.com/beauknowssoftware/go-gql-gen/internal/gogen"
 	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
 )
 
-func hasResolveDirective(fn parse.FieldNode) bool {
-	for _, n := range fn.Directives {
-		if dn, ok := n.(parse.DirectiveNode); ok && dn.Name == "resolve" {
-			return true
-		}
+// Version is the version of the manifest format. It changes whenever
+// fields are renamed or change meaning.
+const Version = 2
+
+// Document is the manifest as written by Generate.
+type Document struct {
+	Version   int     `json:"version"`
+	Resolvers []Entry `json:"resolvers"`
+}
+
+// Entry describes a field with the resolve directive.
+type Entry struct {
+	Type  string `json:"type"`
+	Field string `json:"field"`
+	// Batch is set for fields resolved in batches with
+	// @resolve(batch: true).
+	Batch bool `json:"batch,omitempty"`
+	// Operation is query, mutation or subscription for the fields of root
+	// types and field for the others.
+	Operation  string      `json:"operation"`
+	Arguments  []Argument  `json:"arguments"`
+	Returns    TypeRef     `json:"returns"`
+	Directives []Directive `json:"directives"`
+	Source     Source      `json:"source"`
+}
+
+// Argument is an argument of a resolved field.
+type Argument struct {
+	Name string  `json:"name"`
+	Type TypeRef `json:"type"`
+	// DefaultValue is the default as written in the schema.
+	DefaultValue string `json:"defaultValue,omitempty"`
+}
+
+// TypeRef describes a type along with its nullability and list shape.
+type TypeRef struct {
+	Name            string `json:"name"`
+	NonNull         bool   `json:"nonNull"`
+	List            bool   `json:"list"`
+	NonNullElements bool   `json:"nonNullElements"`
+	// SDL is the type as written in the schema, such as [Other!]!.
+	SDL string `json:"sdl"`
+}
+
+// Directive is a directive of a resolved field. Its arguments hold JSON
+// values.
+type Directive struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+// Source is where a field is declared. File is empty when the schema was
+// read from stdin. Line starts at 1.
+type Source struct {
+	File string `json:"file,omitempty"`
+	Line int    `json:"line"`
+}
+
+func typeRef(n parse.Node) TypeRef {
+	tn, _ := n.(parse.TypeNode)
+	return TypeRef{
+		Name:            tn.Name,
+		NonNull:         tn.Required,
+		List:            tn.Multiple,
+		NonNullElements: tn.NonNullElements,
+		SDL:             parse.PrintType(n),
 	}
-	return false
 }
 
-func Run() {
-	schemaBytes, err := ioutil.ReadAll(os.Stdin)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to read schema from stdin: %v\n", err)
-		os.Exit(1)
+// value converts a GraphQL value to its JSON form. Enum values become
+// strings.
+func value(n parse.Node) interface{} {
+	switch vn := n.(type) {
+	case parse.ValueNode:
+		switch vn.Kind {
+		case parse.IntValue, parse.FloatValue:
+			return json.Number(vn.Value)
+		case parse.BooleanValue:
+			return vn.Value == "true"
+		case parse.NullValue:
+			return nil
+		default:
+			return vn.Value
+		}
+	case parse.ListValueNode:
+		values := make([]interface{}, 0, len(vn.Values))
+		for _, n := range vn.Values {
+			values = append(values, value(n))
+		}
+		return values
+	case parse.ObjectValueNode:
+		return arguments(vn.Fields)
 	}
-	schema := string(schemaBytes)
+	return nil
+}
 
-	p := parse.New(parse.NewLexer(schema))
-	rnode, perr := p.Parse()
-	if perr != nil {
-		fmt.Fprintf(os.Stderr, "failed to parse schema: %v (%v)\n", perr.Error, perr.Token)
-		os.Exit(1)
+func arguments(nodes []parse.Node) map[string]interface{} {
+	args := make(map[string]interface{})
+	for _, n := range nodes {
+		if an, ok := n.(parse.ArgumentNode); ok {
+			args[an.Name] = value(an.Value)
+		}
 	}
+	return args
+}
 
-	type Entry struct {
-		Type  string `json:"type"`
-		Field string `json:"field"`
+func entry(tdn parse.TypeDefNode, fn parse.FieldNode, operation, file string) Entry {
+	if operation == "" {
+		operation = "field"
+	}
+	e := Entry{
+		Type:       tdn.Name,
+		Field:      fn.Name,
+		Batch:      gogen.Batch(fn),
+		Operation:  operation,
+		Arguments:  make([]Argument, 0, len(fn.Params)),
+		Returns:    typeRef(fn.Type),
+		Directives: make([]Directive, 0, len(fn.Directives)),
+		Source:     Source{File: file, Line: fn.Loc().Line + 1},
+	}
+	for _, n := range fn.Params {
+		pn := n.(parse.ParamNode)
+		a := Argument{Name: pn.Name, Type: typeRef(pn.Type)}
+		if pn.DefaultValue != nil {
+			a.DefaultValue = parse.PrintValue(pn.DefaultValue)
+		}
+		e.Arguments = append(e.Arguments, a)
+	}
+	for _, n := range fn.Directives {
+		if dn, ok := n.(parse.DirectiveNode); ok {
+			e.Directives = append(e.Directives, Directive{Name: dn.Name, Arguments: arguments(dn.Arguments)})
+		}
 	}
+	return e
+}
+
+// Manifest lists the fields of the schema that have the resolve directive.
+// files names the schema file of each definition of the document, when
+// the schema was read from files.
+func Manifest(rnode parse.Node, files []string) []Entry {
+	operations := gogen.Operations(rnode)
 	entries := make([]Entry, 0)
-	parse.Traverse(rnode, func(n parse.Node) bool {
-		if tdn, ok := n.(parse.TypeDefNode); ok {
-			for _, n := range tdn.Fields {
-				fn := n.(parse.FieldNode)
-				if hasResolveDirective(fn) {
-					entries = append(entries, Entry{
-						Type:  tdn.Name,
-						Field: fn.Name,
-					})
-				}
+	doc, _ := rnode.(parse.DocumentNode)
+	for i, n := range doc.Definitions {
+		tdn, ok := n.(parse.TypeDefNode)
+		if !ok {
+			continue
+		}
+		var file string
+		if i < len(files) {
+			file = files[i]
+		}
+		for _, n := range tdn.Fields {
+			fn := n.(parse.FieldNode)
+			if gogen.Resolved(fn) {
+				entries = append(entries, entry(tdn, fn, operations[tdn.Name], file))
 			}
-			return false
 		}
-		return true
-	})
+	}
+	return entries
+}
+
+// Generate writes the manifest of the schema to w, see Manifest. The
+// cloudformation, sam and terraform formats declare an AppSync resolver
+// for each entry instead.
+func Generate(w io.Writer, rnode parse.Node, files []string, o Options) error {
+	entries := Manifest(rnode, files)
+	if o.MaxBatchSize == 0 {
+		o.MaxBatchSize = DefaultMaxBatchSize
+	}
+	switch o.Format {
+	case "", "json":
+	case "cloudformation":
+		return writeCloudFormation(w, entries, o, false)
+	case "sam":
+		return writeCloudFormation(w, entries, o, true)
+	case "terraform":
+		return writeTerraform(w, entries, o)
+	default:
+		return fmt.Errorf("unknown format %q", o.Format)
+	}
 
-	d, err := json.MarshalIndent(entries, "", "  ")
+	d, err := json.MarshalIndent(Document{Version: Version, Resolvers: entries}, "", "  ")
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to marshal manifest: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to marshal manifest: %v", err)
 	}
-	os.Stdout.Write(d)
+	_, err = w.Write(append(d, '\n'))
+	return err
 }
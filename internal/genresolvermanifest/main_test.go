@@ -27,15 +27,35 @@ func ReadFile(t *testing.T, filename string) string {
 }
 
 func Test_Main(t *testing.T) {
-	tests := []string{
-		"types",
+	tests := map[string]struct {
+		schema string
+		args   []string
+		golden string
+	}{
+		"types":      {schema: "types", golden: "types.json"},
+		"directives": {schema: "directives", golden: "directives.json"},
+		"cloudformation": {
+			schema: "directives",
+			args:   []string{"-format", "cloudformation", "-data-source", "lambda"},
+			golden: "directives.cloudformation.yaml",
+		},
+		"sam": {
+			schema: "directives",
+			args:   []string{"-format", "sam", "-data-source", "lambda", "-api", "Api", "-max-batch-size", "25"},
+			golden: "directives.sam.yaml",
+		},
+		"terraform": {
+			schema: "directives",
+			args:   []string{"-format", "terraform", "-data-source", "lambda"},
+			golden: "directives.tf",
+		},
 	}
 
-	for _, name := range tests {
+	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			gqls := OpenFile(t, "testdata/"+name+".graphqls")
+			gqls := OpenFile(t, "testdata/"+test.schema+".graphqls")
 
-			cmd := exec.Command("gen-resolver-manifest", "-package", "test")
+			cmd := exec.Command("gen-resolver-manifest", append([]string{"-package", "test"}, test.args...)...)
 			var outBuff, errBuff bytes.Buffer
 			cmd.Stdin = gqls
 			cmd.Stdout = &outBuff
@@ -45,7 +65,7 @@ func Test_Main(t *testing.T) {
 				t.Fatalf("failed to run %v\n%v\n%v", err, outBuff.String(), errBuff.String())
 			}
 
-			expected := ReadFile(t, "testdata/"+name+".json")
+			expected := ReadFile(t, "testdata/"+test.golden)
 			if diff := cmp.Diff(expected, outBuff.String()); diff != "" {
 				t.Fatalf("mismatch (-expected,+got) %v", diff)
 			}
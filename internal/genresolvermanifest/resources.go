@@ -0,0 +1,163 @@
+package genresolvermanifest
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Formats lists the output formats of Generate.
+var Formats = []string{"json", "cloudformation", "sam", "terraform"}
+
+// DefaultMaxBatchSize is the batch size of batched resolvers when none is
+// given.
+const DefaultMaxBatchSize = 10
+
+// Options configure the output of Generate.
+type Options struct {
+	// Format is one of Formats, json when empty.
+	Format string
+	// DataSource is the data source of the resolvers that do not name one
+	// with @resolve(dataSource: "name").
+	DataSource string
+	// API is the CloudFormation logical ID or Terraform name of the GraphQL
+	// API, GraphQLApi and api when empty.
+	API string
+	// MaxBatchSize is the batch size of batched resolvers.
+	MaxBatchSize int
+}
+
+// responseTemplate unwraps the appsync.Response that the generated Handler
+// returns for a single field, raising its error as a GraphQL error. Batched
+// fields need no template, as AppSync unwraps the responses of BatchHandler
+// itself. The request is passed to the Lambda function as it is.
+var responseTemplate = []string{
+	"#if($ctx.result.errorMessage)",
+	"  $util.error($ctx.result.errorMessage, $ctx.result.errorType)",
+	"#end",
+	"$util.toJson($ctx.result.data)",
+}
+
+var dataSourceName = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// dataSource returns the data source of a resolver.
+func (e Entry) dataSource(def string) (string, error) {
+	ds := def
+	for _, d := range e.Directives {
+		if s, ok := d.Arguments["dataSource"].(string); ok && d.Name == "resolve" {
+			ds = s
+		}
+	}
+	if ds == "" {
+		return "", fmt.Errorf("%v.%v: no data source, set @resolve(dataSource: \"name\") or a default data source", e.Type, e.Field)
+	}
+	if !dataSourceName.MatchString(ds) {
+		return "", fmt.Errorf("%v.%v: invalid data source name %q", e.Type, e.Field, ds)
+	}
+	return ds, nil
+}
+
+// logicalID returns a CloudFormation logical ID, which is alphanumeric.
+func logicalID(parts ...string) string {
+	var b strings.Builder
+	for _, p := range parts {
+		upper := true
+		for _, r := range p {
+			if r == '_' {
+				upper = true
+				continue
+			}
+			if upper {
+				r = unicode.ToUpper(r)
+				upper = false
+			}
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// snake returns the snake case form of a GraphQL name for Terraform.
+func snake(name string) string {
+	var b strings.Builder
+	rs := []rune(name)
+	for i, r := range rs {
+		if unicode.IsUpper(r) {
+			if i > 0 && rs[i-1] != '_' && (!unicode.IsUpper(rs[i-1]) || (i+1 < len(rs) && unicode.IsLower(rs[i+1]))) {
+				b.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func writeCloudFormation(w io.Writer, entries []Entry, o Options, sam bool) error {
+	api := o.API
+	if api == "" {
+		api = "GraphQLApi"
+	}
+	if sam {
+		fmt.Fprintln(w, "Transform: AWS::Serverless-2016-10-31")
+	}
+	fmt.Fprintln(w, "Resources:")
+	for _, e := range entries {
+		ds, err := e.dataSource(o.DataSource)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "  %v:\n", logicalID(e.Type, e.Field, "Resolver"))
+		fmt.Fprintln(w, "    Type: AWS::AppSync::Resolver")
+		fmt.Fprintln(w, "    Properties:")
+		fmt.Fprintf(w, "      ApiId: !GetAtt %v.ApiId\n", api)
+		fmt.Fprintf(w, "      TypeName: %v\n", e.Type)
+		fmt.Fprintf(w, "      FieldName: %v\n", e.Field)
+		fmt.Fprintf(w, "      DataSourceName: %v\n", ds)
+		fmt.Fprintln(w, "      Kind: UNIT")
+		if e.Batch {
+			fmt.Fprintf(w, "      MaxBatchSize: %v\n", o.MaxBatchSize)
+			continue
+		}
+		fmt.Fprintln(w, "      ResponseMappingTemplate: |")
+		for _, l := range responseTemplate {
+			fmt.Fprintf(w, "        %v\n", l)
+		}
+	}
+	return nil
+}
+
+func writeTerraform(w io.Writer, entries []Entry, o Options) error {
+	api := o.API
+	if api == "" {
+		api = "api"
+	}
+	for i, e := range entries {
+		ds, err := e.dataSource(o.DataSource)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "resource \"aws_appsync_resolver\" %q {\n", snake(e.Type)+"_"+snake(e.Field))
+		fmt.Fprintf(w, "  api_id      = aws_appsync_graphql_api.%v.id\n", api)
+		fmt.Fprintf(w, "  type        = %q\n", e.Type)
+		fmt.Fprintf(w, "  field       = %q\n", e.Field)
+		fmt.Fprintf(w, "  data_source = %q\n", ds)
+		fmt.Fprintf(w, "  kind        = %q\n", "UNIT")
+		if e.Batch {
+			fmt.Fprintf(w, "\n  max_batch_size = %v\n", o.MaxBatchSize)
+		} else {
+			fmt.Fprintln(w, "\n  response_template = <<-EOT")
+			for _, l := range responseTemplate {
+				fmt.Fprintf(w, "    %v\n", l)
+			}
+			fmt.Fprintln(w, "  EOT")
+		}
+		fmt.Fprintln(w, "}")
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+resource "aws_appsync_resolver" "account_owner" {
+  api_id      = aws_appsync_graphql_api.api.id
+  type        = "Account"
+  field       = "owner"
+  data_source = "users"
+  kind        = "UNIT"
+
+  max_batch_size = 10
+}
+
+resource "aws_appsync_resolver" "account_transactions" {
+  api_id      = aws_appsync_graphql_api.api.id
+  type        = "Account"
+  field       = "transactions"
+  data_source = "lambda"
+  kind        = "UNIT"
+
+  response_template = <<-EOT
+    #if($ctx.result.errorMessage)
+      $util.error($ctx.result.errorMessage, $ctx.result.errorType)
+    #end
+    $util.toJson($ctx.result.data)
+  EOT
+}
+
+resource "aws_appsync_resolver" "query_account" {
+  api_id      = aws_appsync_graphql_api.api.id
+  type        = "Query"
+  field       = "account"
+  data_source = "accounts"
+  kind        = "UNIT"
+
+  response_template = <<-EOT
+    #if($ctx.result.errorMessage)
+      $util.error($ctx.result.errorMessage, $ctx.result.errorType)
+    #end
+    $util.toJson($ctx.result.data)
+  EOT
+}
+
+resource "aws_appsync_resolver" "mutation_deposit" {
+  api_id      = aws_appsync_graphql_api.api.id
+  type        = "Mutation"
+  field       = "deposit"
+  data_source = "lambda"
+  kind        = "UNIT"
+
+  response_template = <<-EOT
+    #if($ctx.result.errorMessage)
+      $util.error($ctx.result.errorMessage, $ctx.result.errorType)
+    #end
+    $util.toJson($ctx.result.data)
+  EOT
+}
+
+resource "aws_appsync_resolver" "subscription_deposits" {
+  api_id      = aws_appsync_graphql_api.api.id
+  type        = "Subscription"
+  field       = "deposits"
+  data_source = "lambda"
+  kind        = "UNIT"
+
+  response_template = <<-EOT
+    #if($ctx.result.errorMessage)
+      $util.error($ctx.result.errorMessage, $ctx.result.errorType)
+    #end
+    $util.toJson($ctx.result.data)
+  EOT
+}
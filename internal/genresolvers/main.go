@@ -0,0 +1,78 @@
+package genresolvers
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/beauknowssoftware/go-gql-gen/internal/gogen"
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+// Generate writes the resolver interfaces for the @resolve fields of the
+// schema in package pkg to w. Each type with such fields gets an interface
+// named after it, and the Resolver interface returns the resolvers of every
+// type. The types and argument structs are expected in the same package, as
+// gen-gql-inputs writes them.
+//
+// Fields marked with @resolve(batch: true) take the parents and arguments
+// of a batch and return a result for each. The errors are either nil, hold
+// an error for each result or hold a single error for the whole batch.
+func Generate(w io.Writer, rnode parse.Node, pkg string, o gogen.Options) error {
+	f := gogen.NewFile(pkg, rnode, o)
+	var resolvers [][2]string
+	parse.Traverse(rnode, func(n parse.Node) bool {
+		tdn, ok := n.(parse.TypeDefNode)
+		if !ok {
+			return true
+		}
+		if tdn.Input {
+			return false
+		}
+		var fields []parse.FieldNode
+		for _, n := range tdn.Fields {
+			if fn := n.(parse.FieldNode); gogen.Resolved(fn) {
+				fields = append(fields, fn)
+			}
+		}
+		if len(fields) == 0 {
+			return false
+		}
+
+		name := f.TypeName(tdn.Name) + "Resolver"
+		f.Declare(name, "resolver of "+tdn.Name)
+		resolvers = append(resolvers, [2]string{f.TypeName(tdn.Name), name})
+		fmt.Fprintf(f, "\ntype %v interface {\n", name)
+		for _, fn := range fields {
+			t := f.Type(tdn.Name, fn.Name, fn.Type.(parse.TypeNode))
+			fmt.Fprintf(f, "\t%v(ctx %v", f.FieldName(tdn.Name, fn.Name), f.Import("context.Context"))
+			if gogen.Batch(fn) {
+				if f.Operation(tdn.Name) == "" {
+					fmt.Fprintf(f, ", parents []*%v", f.Named(tdn.Name))
+				}
+				if len(fn.Params) > 0 {
+					fmt.Fprintf(f, ", args []%v", f.ArgsName(tdn.Name, fn.Name))
+				}
+				fmt.Fprintf(f, ") ([]%v, []error)\n", t)
+				continue
+			}
+			if f.Operation(tdn.Name) == "" {
+				fmt.Fprintf(f, ", parent *%v", f.Named(tdn.Name))
+			}
+			if len(fn.Params) > 0 {
+				fmt.Fprintf(f, ", args %v", f.ArgsName(tdn.Name, fn.Name))
+			}
+			fmt.Fprintf(f, ") (%v, error)\n", t)
+		}
+		fmt.Fprintln(f, "}")
+		return false
+	})
+
+	f.Declare("Resolver", "root resolver")
+	fmt.Fprintln(f, "\ntype Resolver interface {")
+	for _, r := range resolvers {
+		fmt.Fprintf(f, "\t%v() %v\n", r[0], r[1])
+	}
+	fmt.Fprintln(f, "}")
+	_, err := f.WriteTo(w)
+	return err
+}
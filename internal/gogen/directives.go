@@ -0,0 +1,116 @@
+package gogen
+
+import (
+	"fmt"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+// goField holds the arguments of a @goField directive.
+type goField struct {
+	name string
+	typ  string
+}
+
+func directiveValue(directives []parse.Node, directive, arg string) (parse.ValueNode, bool) {
+	for _, n := range directives {
+		dn, ok := n.(parse.DirectiveNode)
+		if !ok || dn.Name != directive {
+			continue
+		}
+		for _, n := range dn.Arguments {
+			if an, ok := n.(parse.ArgumentNode); ok && an.Name == arg {
+				vn, ok := an.Value.(parse.ValueNode)
+				return vn, ok
+			}
+		}
+	}
+	return parse.ValueNode{}, false
+}
+
+func directiveArg(directives []parse.Node, directive, arg string) (string, bool) {
+	vn, ok := directiveValue(directives, directive, arg)
+	if !ok || vn.Kind != parse.StringValue {
+		return "", false
+	}
+	return vn.Value, true
+}
+
+// Resolved reports whether the field is marked with @resolve.
+func Resolved(fn parse.FieldNode) bool {
+	for _, n := range fn.Directives {
+		if dn, ok := n.(parse.DirectiveNode); ok && dn.Name == "resolve" {
+			return true
+		}
+	}
+	return false
+}
+
+// Batch reports whether the field is resolved in batches, as marked with
+// @resolve(batch: true).
+func Batch(fn parse.FieldNode) bool {
+	vn, ok := directiveValue(fn.Directives, "resolve", "batch")
+	return ok && vn.Kind == parse.BooleanValue && vn.Value == "true"
+}
+
+// readDirectives collects @goModel(model: "example.com/pkg.Type") on type
+// definitions and @goField(name: "Name", type: "pkg.Type") on fields and
+// arguments.
+func (f *File) readDirectives(rnode parse.Node) {
+	model := func(name string, directives []parse.Node, models map[string]string) {
+		ref, ok := directiveArg(directives, "goModel", "model")
+		if !ok {
+			return
+		}
+		if _, _, _, err := splitRef(ref); err != nil {
+			f.errs = append(f.errs, fmt.Sprintf("%v: invalid @goModel: %v", name, err))
+			return
+		}
+		models[name] = ref
+	}
+	field := func(key string, directives []parse.Node) {
+		var gf goField
+		gf.name, _ = directiveArg(directives, "goField", "name")
+		gf.typ, _ = directiveArg(directives, "goField", "type")
+		if gf.typ != "" {
+			if _, _, _, err := splitRef(gf.typ); err != nil {
+				f.errs = append(f.errs, fmt.Sprintf("%v: invalid @goField type: %v", key, err))
+				return
+			}
+		}
+		if gf != (goField{}) {
+			f.goFields[key] = gf
+		}
+	}
+
+	parse.Traverse(rnode, func(n parse.Node) bool {
+		switch dn := n.(type) {
+		case parse.DocumentNode:
+			return true
+		case parse.TypeDefNode:
+			model(dn.Name, dn.Directives, f.models)
+			for _, n := range dn.Fields {
+				fn := n.(parse.FieldNode)
+				field(dn.Name+"."+fn.Name, fn.Directives)
+				for _, n := range fn.Params {
+					pn := n.(parse.ParamNode)
+					field(dn.Name+"."+fn.Name+"."+pn.Name, pn.Directives)
+				}
+			}
+		case parse.EnumDefNode:
+			model(dn.Name, dn.Directives, f.models)
+		case parse.UnionDefNode:
+			model(dn.Name, dn.Directives, f.models)
+		case parse.ScalarDefNode:
+			model(dn.Name, dn.Directives, f.scalarModels)
+		}
+		return false
+	})
+}
+
+// External reports whether the schema type maps onto an existing Go type
+// through @goModel, in which case no Go type is generated for it.
+func (f *File) External(name string) bool {
+	_, ok := f.models[name]
+	return ok
+}
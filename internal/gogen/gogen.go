@@ -0,0 +1,511 @@
+package gogen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/scanner"
+	"go/token"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+// Nullable policies decide how nullable schema types map to Go. Non-null
+// types are always values.
+const (
+	// NullablePointer makes nullable types pointers, which is the default.
+	NullablePointer = "pointer"
+	// NullableOptional wraps nullable types in gql.Optional.
+	NullableOptional = "optional"
+	// NullableValue uses values, so null becomes the zero value.
+	NullableValue = "value"
+)
+
+const (
+	optionalType = "github.com/beauknowssoftware/go-gql-gen/pkg/gql.Optional"
+	inputType    = "github.com/beauknowssoftware/go-gql-gen/pkg/gql.Input"
+)
+
+// Options configure how schema types map to Go.
+type Options struct {
+	// Scalars maps scalar names to Go types. Types outside the generated
+	// package are written as "time.Time" or "github.com/org/pkg.Type".
+	Scalars map[string]string
+	// Types renames the Go types generated for schema types.
+	Types    map[string]string
+	Nullable string
+	// TriState wraps nullable input fields and arguments in gql.Input, which
+	// tells absent fields from null ones.
+	TriState bool
+	// Initialisms are written in upper case on top of the golint ones.
+	Initialisms []string
+	// Fields renames the Go fields of "Type.field" and of arguments as
+	// "Type.field.argument".
+	Fields map[string]string
+	// Links sets the keys carried by the link of the @resolve field
+	// "Type.field", mapping key names to scalar types.
+	Links map[string]map[string]string
+}
+
+var graphQLName = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+var builtinScalars = map[string]string{
+	"String":  "string",
+	"Int":     "int",
+	"Float":   "float64",
+	"Boolean": "bool",
+	"ID":      "ID",
+}
+
+// Check reports whether the options are well formed.
+func (o Options) Check() error {
+	switch o.Nullable {
+	case "", NullablePointer, NullableOptional, NullableValue:
+	default:
+		return fmt.Errorf("unknown nullable policy %q", o.Nullable)
+	}
+	for key, name := range o.Fields {
+		if !token.IsIdentifier(name) || !token.IsExported(name) {
+			return fmt.Errorf("invalid Go name %q for field %v", name, key)
+		}
+	}
+	for field, keys := range o.Links {
+		for name := range keys {
+			if !graphQLName.MatchString(name) || strings.HasPrefix(name, "__") {
+				return fmt.Errorf("invalid link key %q for field %v", name, field)
+			}
+		}
+	}
+	for scalar, ref := range o.Scalars {
+		if _, _, _, err := splitRef(ref); err != nil {
+			return fmt.Errorf("invalid Go type for scalar %v: %v", scalar, err)
+		}
+	}
+	return nil
+}
+
+// splitRef splits a Go type reference into its import path, the pointer
+// and slice prefix and the name of the type.
+func splitRef(ref string) (string, string, string, error) {
+	if ref == "" {
+		return "", "", "", fmt.Errorf("empty type")
+	}
+	prefix := ""
+	for strings.HasPrefix(ref[len(prefix):], "*") || strings.HasPrefix(ref[len(prefix):], "[]") {
+		if ref[len(prefix)] == '*' {
+			prefix += "*"
+		} else {
+			prefix += "[]"
+		}
+	}
+	name := ref[len(prefix):]
+	dot := strings.LastIndex(name, ".")
+	if dot < 0 {
+		return "", prefix, name, nil
+	}
+	importPath, typeName := name[:dot], name[dot+1:]
+	if importPath == "" || typeName == "" || strings.HasSuffix(importPath, "/") {
+		return "", "", "", fmt.Errorf("malformed type %q", ref)
+	}
+	return importPath, prefix, typeName, nil
+}
+
+var majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// packageName guesses the name of the package at importPath the way
+// goimports does: from the last path element, skipping a "/v2" major
+// version, dropping a "go-" prefix and cutting at the first character that
+// cannot appear in an identifier, so "gopkg.in/yaml.v3" is yaml.
+func packageName(importPath string) string {
+	base := path.Base(importPath)
+	if majorVersion.MatchString(base) && path.Dir(importPath) != "." {
+		base = path.Base(path.Dir(importPath))
+	}
+	base = strings.TrimPrefix(base, "go-")
+	if i := strings.IndexFunc(base, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}); i >= 0 {
+		base = base[:i]
+	}
+	if !token.IsIdentifier(base) || base == "_" {
+		return "pkg"
+	}
+	return base
+}
+
+// File collects the body of a generated Go file along with the imports it
+// needs.
+type File struct {
+	Options
+	Namer
+	Package string
+	// imports maps import paths to the names the code refers to them by.
+	imports map[string]string
+	body    bytes.Buffer
+	// contains maps each type to the types its structs hold by value.
+	contains map[string][]string
+	inputs   map[string]bool
+	// operations maps the root types to their operation.
+	operations map[string]string
+	// declared maps the top level Go names to what declared them.
+	declared map[string]string
+	errs     []string
+	// models, scalarModels and goFields hold the @goModel and @goField
+	// directives.
+	models       map[string]string
+	scalarModels map[string]string
+	goFields     map[string]goField
+}
+
+// NewFile starts a file for the types of the schema rnode.
+func NewFile(pkg string, rnode parse.Node, o Options) *File {
+	f := &File{
+		Options:    o,
+		Namer:      NewNamer(o.Initialisms),
+		Package:    pkg,
+		imports:    make(map[string]string),
+		contains:   make(map[string][]string),
+		inputs:     make(map[string]bool),
+		operations: Operations(rnode),
+		declared:   make(map[string]string),
+		models:     make(map[string]string),
+		goFields:   make(map[string]goField),
+
+		scalarModels: make(map[string]string),
+	}
+	f.readDirectives(rnode)
+	parse.Traverse(rnode, func(n parse.Node) bool {
+		tdn, ok := n.(parse.TypeDefNode)
+		if !ok {
+			return true
+		}
+		if f.External(tdn.Name) {
+			return false
+		}
+		f.inputs[tdn.Name] = tdn.Input
+		for _, n := range tdn.Fields {
+			if tn, ok := n.(parse.FieldNode).Type.(parse.TypeNode); ok && f.byValue(tn, tdn.Input) {
+				f.contains[tdn.Name] = append(f.contains[tdn.Name], tn.Name)
+			}
+		}
+		return false
+	})
+	return f
+}
+
+// byValue reports whether a field of type tn holds its value inline.
+func (f *File) byValue(tn parse.TypeNode, input bool) bool {
+	if tn.Multiple {
+		return false
+	}
+	return tn.Required || (input && f.TriState) || (f.Nullable != "" && f.Nullable != NullablePointer)
+}
+
+// recursive reports whether a struct of type from would contain itself
+// through fields of type to.
+func (f *File) recursive(from, to string) bool {
+	seen := make(map[string]bool)
+	var reaches func(t string) bool
+	reaches = func(t string) bool {
+		if t == from {
+			return true
+		}
+		if seen[t] {
+			return false
+		}
+		seen[t] = true
+		for _, c := range f.contains[t] {
+			if reaches(c) {
+				return true
+			}
+		}
+		return false
+	}
+	return reaches(to)
+}
+
+func (f *File) Write(p []byte) (int, error) {
+	return f.body.Write(p)
+}
+
+// Import adds the package of a Go type reference to the imports and
+// returns the type as written in code.
+func (f *File) Import(ref string) string {
+	importPath, prefix, typeName, err := splitRef(ref)
+	if err != nil {
+		return ref
+	}
+	if importPath == "" {
+		return prefix + typeName
+	}
+	return prefix + f.importName(importPath) + "." + typeName
+}
+
+// importName returns the name code refers to the package at importPath by.
+// A package whose guessed name is already taken by another import is
+// renamed with a number, as in model2.
+func (f *File) importName(importPath string) string {
+	if name, ok := f.imports[importPath]; ok {
+		return name
+	}
+	taken := make(map[string]bool)
+	for _, name := range f.imports {
+		taken[name] = true
+	}
+	base := packageName(importPath)
+	name := base
+	for i := 2; taken[name] || token.IsKeyword(name); i++ {
+		name = fmt.Sprintf("%v%v", base, i)
+	}
+	f.imports[importPath] = name
+	return name
+}
+
+// qualified returns the type of a Go type reference as written in code and
+// whether its package has been imported.
+func (f *File) qualified(ref string) (string, bool) {
+	importPath, prefix, typeName, err := splitRef(ref)
+	if err != nil {
+		return "", false
+	}
+	name, ok := f.imports[importPath]
+	return prefix + name + "." + typeName, ok
+}
+
+// Scalar returns the Go type of a scalar and whether name is a scalar.
+func (f *File) Scalar(name string) (string, bool) {
+	if ref, ok := f.Scalars[name]; ok {
+		return f.Import(ref), true
+	}
+	if ref, ok := f.scalarModels[name]; ok {
+		return f.Import(ref), true
+	}
+	t, ok := builtinScalars[name]
+	return t, ok
+}
+
+// FieldName returns the Go name of a field of a schema type, or of an
+// argument when field is "field.argument".
+func (f *File) FieldName(typeName, field string) string {
+	if n, ok := f.Fields[typeName+"."+field]; ok {
+		return n
+	}
+	if gf := f.goFields[typeName+"."+field]; gf.name != "" {
+		return gf.name
+	}
+	if i := strings.LastIndex(field, "."); i >= 0 {
+		field = field[i+1:]
+	}
+	return f.Exported(field)
+}
+
+// Declare records a top level Go name. Two declarations of the same name
+// make WriteTo fail.
+func (f *File) Declare(name, what string) {
+	if other, ok := f.declared[name]; ok {
+		f.errs = append(f.errs, fmt.Sprintf("%v and %v are both declared as %v", other, what, name))
+		return
+	}
+	f.declared[name] = what
+}
+
+// Struct tracks the fields of a struct so that two schema fields never
+// share a Go name.
+type Struct struct {
+	f        *File
+	typeName string
+	fields   map[string]string
+}
+
+// Struct declares a struct for the schema type typeName. what describes
+// the struct in errors.
+func (f *File) Struct(name, typeName, what string) *Struct {
+	f.Declare(name, what)
+	return &Struct{f: f, typeName: typeName, fields: make(map[string]string)}
+}
+
+// Field returns the Go name of a field, see FieldName.
+func (s *Struct) Field(field string) string {
+	name := s.f.FieldName(s.typeName, field)
+	s.add(name, field)
+	return name
+}
+
+// Embed records an embedded field of type t.
+func (s *Struct) Embed(t string) {
+	s.add(t, "embedded "+t)
+}
+
+// Method records a method, which may not share the name of a field.
+func (s *Struct) Method(name string) {
+	s.add(name, "method "+name)
+}
+
+func (s *Struct) add(name, field string) {
+	if other, ok := s.fields[name]; ok {
+		s.f.errs = append(s.f.errs, fmt.Sprintf("%v: %v and %v both map to the Go field %v, rename one under \"fields\"", s.typeName, other, field, name))
+		return
+	}
+	s.fields[name] = field
+}
+
+// ArgsName returns the Go name of the struct holding the arguments of a
+// field.
+func (f *File) ArgsName(typeName, field string) string {
+	return f.TypeName(typeName) + f.FieldName(typeName, field) + "Args"
+}
+
+// Named returns the Go type of the schema type name, which is the
+// @goModel type when there is one.
+func (f *File) Named(name string) string {
+	if ref, ok := f.models[name]; ok {
+		return f.Import(ref)
+	}
+	return f.TypeName(name)
+}
+
+// TypeName returns the Go name of a schema type: its name in Types, or
+// else the exported name, so that type user becomes User and type type
+// becomes Type.
+func (f *File) TypeName(name string) string {
+	if n, ok := f.Types[name]; ok {
+		return n
+	}
+	return f.Exported(name)
+}
+
+// Type returns the Go type of a field of the type parent, or of an
+// argument when field is "field.argument". Lists are slices, which are nil
+// when the list is null. Fields that would make a struct contain itself
+// are pointers.
+func (f *File) Type(parent, field string, tn parse.TypeNode) string {
+	if gf := f.goFields[parent+"."+field]; gf.typ != "" {
+		return f.Import(gf.typ)
+	}
+	name, scalar := f.Scalar(tn.Name)
+	if !scalar {
+		name = f.Named(tn.Name)
+	}
+	if tn.Multiple {
+		if !tn.NonNullElements {
+			name = f.nullable(name)
+		}
+		return "[]" + name
+	}
+	if !strings.Contains(field, ".") && f.byValue(tn, false) && f.recursive(parent, tn.Name) {
+		return "*" + name
+	}
+	if !tn.Required {
+		return f.nullable(name)
+	}
+	return name
+}
+
+// InputType returns the Go type of a field of the input type parent, or of
+// an argument, see Type.
+func (f *File) InputType(parent, field string, tn parse.TypeNode) string {
+	if gf := f.goFields[parent+"."+field]; !f.TriState || tn.Required || gf.typ != "" {
+		return f.Type(parent, field, tn)
+	}
+	// The wrapper already says whether the field is null, so it holds the
+	// type as if it were non-null.
+	required := tn
+	required.Required = true
+	return f.Import(inputType) + "[" + f.Type(parent, field, required) + "]"
+}
+
+// InputTag returns the struct tag of the input field or argument name of Go
+// type t. gql.Input fields are omitted while absent, so that marshalling
+// them again does not turn "leave alone" into "clear".
+func (f *File) InputTag(name, t string) string {
+	if wrapper, ok := f.qualified(inputType); ok && strings.HasPrefix(t, wrapper+"[") {
+		return fmt.Sprintf("`json:\"%v,omitzero\"`", name)
+	}
+	return fmt.Sprintf("`json:\"%v\"`", name)
+}
+
+func (f *File) nullable(t string) string {
+	switch f.Nullable {
+	case NullableOptional:
+		return f.Import(optionalType) + "[" + t + "]"
+	case NullableValue:
+		return t
+	default:
+		return "*" + t
+	}
+}
+
+// Format runs generated source through gofmt. Source that does not parse
+// is a bug in a generator, so the error quotes the offending line.
+func Format(src []byte) ([]byte, error) {
+	d, err := format.Source(src)
+	if err == nil {
+		return d, nil
+	}
+	var errs scanner.ErrorList
+	if errors.As(err, &errs) && len(errs) > 0 {
+		lines := strings.Split(string(src), "\n")
+		if l := errs[0].Pos.Line; l > 0 && l <= len(lines) {
+			return nil, fmt.Errorf("generated code is not valid Go: %v\n\t%v", err, strings.TrimSpace(lines[l-1]))
+		}
+	}
+	return nil, fmt.Errorf("generated code is not valid Go: %v", err)
+}
+
+// WriteTo writes the package clause, the imports and then the body, all
+// formatted with gofmt.
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	if len(f.errs) > 0 {
+		return 0, errors.New(strings.Join(f.errs, "\n"))
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "package %v\n\n", f.Package)
+	if len(f.imports) > 0 {
+		// Standard library packages come first, as goimports groups them.
+		var std, other []string
+		for i := range f.imports {
+			if strings.Contains(strings.SplitN(i, "/", 2)[0], ".") {
+				other = append(other, i)
+			} else {
+				std = append(std, i)
+			}
+		}
+		sort.Strings(std)
+		sort.Strings(other)
+		b.WriteString("import (\n")
+		for _, i := range std {
+			f.writeImport(&b, i)
+		}
+		if len(std) > 0 && len(other) > 0 {
+			b.WriteString("\n")
+		}
+		for _, i := range other {
+			f.writeImport(&b, i)
+		}
+		b.WriteString(")\n\n")
+	}
+	b.Write(f.body.Bytes())
+	d, err := Format(b.Bytes())
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(d)
+	return int64(n), err
+}
+
+// writeImport writes an import spec, naming the package whenever its name
+// is not the last element of its path.
+func (f *File) writeImport(b *bytes.Buffer, importPath string) {
+	if name := f.imports[importPath]; name != path.Base(importPath) {
+		fmt.Fprintf(b, "\t%v %q\n", name, importPath)
+		return
+	}
+	fmt.Fprintf(b, "\t%q\n", importPath)
+}
@@ -0,0 +1,84 @@
+package gogen
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+// LinkKey is a value that the link of a @resolve field carries so that the
+// field can be resolved later.
+type LinkKey struct {
+	// Name is the key in JSON, without the __ prefix.
+	Name   string
+	GoName string
+	Type   string
+	// Sibling is set when a field of the parent already carries the key, so
+	// the link does not. Resolvers read such keys from the parent, which
+	// AppSync passes whole as the source of the field.
+	Sibling bool
+}
+
+// Tag returns the struct tag of the key. GraphQL reserves names starting
+// with __, so clients can never select the key and it stays out of API
+// responses, while AppSync still hands it back as the source of the field.
+func (k LinkKey) Tag() string {
+	return fmt.Sprintf("`json:\"__%v,omitempty\"`", k.Name)
+}
+
+// LinkKeys returns the keys of the link of the @resolve field fn of tdn.
+// They are configured under "Type.field" in Links, mapping key names to
+// scalar types; a key naming a field of tdn refers to that field instead of
+// being copied into the link. Without configuration, a field without
+// arguments that returns one object uses its foreign key, named after the
+// field with an Id or ID suffix.
+func (f *File) LinkKeys(tdn parse.TypeDefNode, fn parse.FieldNode) []LinkKey {
+	siblings := make(map[string]parse.TypeNode)
+	for _, n := range tdn.Fields {
+		sfn := n.(parse.FieldNode)
+		siblings[sfn.Name], _ = sfn.Type.(parse.TypeNode)
+	}
+
+	keys, ok := f.Links[tdn.Name+"."+fn.Name]
+	if !ok {
+		tn, _ := fn.Type.(parse.TypeNode)
+		if len(fn.Params) > 0 || tn.Multiple {
+			return nil
+		}
+		if _, scalar := f.Scalar(tn.Name); scalar {
+			return nil
+		}
+		keys = map[string]string{fn.Name + "Id": "ID"}
+		for _, name := range []string{fn.Name + "Id", fn.Name + "ID"} {
+			if stn, ok := siblings[name]; ok {
+				keys = map[string]string{name: stn.Name}
+				break
+			}
+		}
+	}
+
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var lks []LinkKey
+	for _, name := range names {
+		if stn, ok := siblings[name]; ok {
+			if stn.Name != keys[name] || stn.Multiple {
+				f.errs = append(f.errs, fmt.Sprintf("%v.%v: link key %v is a field of %v of type %v, not %v", tdn.Name, fn.Name, name, tdn.Name, stn.Name, keys[name]))
+				continue
+			}
+			lks = append(lks, LinkKey{Name: name, GoName: f.FieldName(tdn.Name, name), Type: f.Type(tdn.Name, name, stn), Sibling: true})
+			continue
+		}
+		t, ok := f.Scalar(keys[name])
+		if !ok {
+			f.errs = append(f.errs, fmt.Sprintf("%v.%v: link key %v has unknown scalar type %v", tdn.Name, fn.Name, name, keys[name]))
+			continue
+		}
+		lks = append(lks, LinkKey{Name: name, GoName: f.Exported(name), Type: t})
+	}
+	return lks
+}
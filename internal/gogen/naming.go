@@ -0,0 +1,97 @@
+package gogen
+
+import (
+	"strings"
+	"unicode"
+)
+
+// commonInitialisms is the list golint checks names against.
+var commonInitialisms = []string{
+	"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP",
+	"HTTPS", "ID", "IP", "JSON", "LHS", "QPS", "RAM", "RHS", "RPC", "SLA",
+	"SMTP", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI", "UID", "UUID",
+	"URI", "URL", "UTF8", "VM", "XML", "XMPP", "XSRF", "XSS",
+}
+
+// Namer turns schema names into Go names.
+type Namer struct {
+	initialisms map[string]bool
+}
+
+// NewNamer returns a Namer that knows the golint initialisms along with
+// extra ones.
+func NewNamer(extra []string) Namer {
+	n := Namer{initialisms: make(map[string]bool)}
+	for _, i := range commonInitialisms {
+		n.initialisms[i] = true
+	}
+	for _, i := range extra {
+		n.initialisms[strings.ToUpper(i)] = true
+	}
+	return n
+}
+
+// words splits a camel, snake or kebab case name into its words. A run of
+// capitals is one word, except that its last letter starts the next word
+// when followed by a lower case letter, as in HTTPServer.
+func words(name string) []string {
+	var ws []string
+	rs := []rune(name)
+	start := -1
+	for i, r := range rs {
+		if r == '_' || r == '-' || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if start >= 0 {
+				ws = append(ws, string(rs[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start >= 0 && unicode.IsUpper(r) {
+			prev := rs[i-1]
+			nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				ws = append(ws, string(rs[start:i]))
+				start = i
+			}
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		ws = append(ws, string(rs[start:]))
+	}
+	return ws
+}
+
+// word capitalizes one word, writing initialisms and their plurals, like
+// IDs, in upper case.
+func (n Namer) word(w string) string {
+	upper := strings.ToUpper(w)
+	if n.initialisms[upper] {
+		return upper
+	}
+	if len(w) > 2 && strings.HasSuffix(w, "s") && n.initialisms[upper[:len(upper)-1]] {
+		return upper[:len(upper)-1] + "s"
+	}
+	if w == upper {
+		w = strings.ToLower(w)
+	}
+	rs := []rune(w)
+	rs[0] = unicode.ToUpper(rs[0])
+	return string(rs)
+}
+
+// Exported returns the exported Go name of a schema name, for example
+// userUrl becomes UserURL and created_at becomes CreatedAt.
+func (n Namer) Exported(name string) string {
+	var b strings.Builder
+	for _, w := range words(name) {
+		b.WriteString(n.word(w))
+	}
+	s := b.String()
+	if s == "" || !unicode.IsLetter([]rune(s)[0]) {
+		s = "X" + s
+	}
+	return s
+}
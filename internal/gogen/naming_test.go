@@ -0,0 +1,48 @@
+package gogen_test
+
+import (
+	"testing"
+
+	"github.com/beauknowssoftware/go-gql-gen/internal/gogen"
+)
+
+func TestNamer(t *testing.T) {
+	n := gogen.NewNamer([]string{"sku"})
+
+	tests := []struct {
+		name     string
+		exported string
+	}{
+		{"id", "ID"},
+		{"myId", "MyID"},
+		{"ids", "IDs"},
+		{"userIds", "UserIDs"},
+		{"userUrl", "UserURL"},
+		{"userURL", "UserURL"},
+		{"HTTPServer", "HTTPServer"},
+		{"apiKey", "APIKey"},
+		{"created_at", "CreatedAt"},
+		{"snake_case_url", "SnakeCaseURL"},
+		{"kebab-case-id", "KebabCaseID"},
+		{"SCREAMING_CASE", "ScreamingCase"},
+		{"__typename", "Typename"},
+		{"pOthers", "POthers"},
+		{"utf8Name", "UTF8Name"},
+		{"field2", "Field2"},
+		{"sku", "SKU"},
+		{"productSkus", "ProductSKUs"},
+		{"status", "Status"},
+		{"type", "Type"},
+		{"func", "Func"},
+		{"range", "Range"},
+		{"_2fa", "X2fa"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := n.Exported(test.name); got != test.exported {
+				t.Fatalf("expected exported %v got %v", test.exported, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package gogen
+
+import "github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+
+// Operations maps the root types of the schema to their operation, such as
+// query. They default to Query, Mutation and Subscription when there is no
+// schema definition.
+func Operations(rnode parse.Node) map[string]string {
+	operations := make(map[string]string)
+	parse.Traverse(rnode, func(n parse.Node) bool {
+		sn, ok := n.(parse.SchemaNode)
+		if !ok {
+			_, doc := n.(parse.DocumentNode)
+			return doc
+		}
+		for _, n := range sn.Fields {
+			fn, ok := n.(parse.FieldNode)
+			if !ok {
+				continue
+			}
+			if tn, ok := fn.Type.(parse.TypeNode); ok {
+				operations[tn.Name] = fn.Name
+			}
+		}
+		return false
+	})
+	if len(operations) == 0 {
+		operations["Query"] = "query"
+		operations["Mutation"] = "mutation"
+		operations["Subscription"] = "subscription"
+	}
+	return operations
+}
+
+// Operation returns the operation, such as query, of a root type and ""
+// for other types.
+func (f *File) Operation(typeName string) string {
+	return f.operations[typeName]
+}
@@ -0,0 +1,117 @@
+package gogen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+const gqlPackage = "github.com/beauknowssoftware/go-gql-gen/pkg/gql"
+
+// Validation collects the checks of the Validate method of a generated
+// input or argument struct.
+type Validation struct {
+	f    *File
+	body strings.Builder
+	// required lists the fields whose checks need to know whether the
+	// struct was decoded with them.
+	required []string
+}
+
+// Validation starts the checks of a struct.
+func (f *File) Validation() *Validation {
+	return &Validation{f: f}
+}
+
+// Field checks the field goName, of Go type t, which holds the schema type
+// tn under name in the path. Required lists and pointers are reported when
+// nil. Other required values cannot be told from the zero value, so they
+// are reported when the struct was decoded from JSON without them.
+// Generated input types are validated in turn.
+func (v *Validation) Field(goName, name, t string, tn parse.TypeNode) {
+	path := fmt.Sprintf("%v(path, %q)", v.f.Import(gqlPackage+".Path"), name)
+	if !tn.Required || strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "*") {
+		v.check(t, "v."+goName, path, tn.Name, tn.Required, tn.NonNullElements, "\t")
+		return
+	}
+	v.required = append(v.required, name)
+	inner := v.f.Validation()
+	inner.check(t, "v."+goName, path, tn.Name, true, tn.NonNullElements, "\t\t")
+	fmt.Fprintf(&v.body, "\tif v.fields.Missing(%q) {\n\t\terrs.Missing(%v)\n\t}", name, path)
+	if inner.body.Len() > 0 {
+		fmt.Fprintf(&v.body, " else {\n%v\t}", inner.body.String())
+	}
+	v.body.WriteString("\n")
+}
+
+func (v *Validation) check(t, expr, path, typeName string, required, nonNullElements bool, indent string) {
+	for _, ref := range []string{inputType, optionalType} {
+		wrapper, ok := v.f.qualified(ref)
+		if ok && strings.HasPrefix(t, wrapper+"[") {
+			inner := v.f.Validation()
+			inner.check(t[len(wrapper)+1:len(t)-1], expr+".Value", path, typeName, false, nonNullElements, indent+"\t")
+			if inner.body.Len() > 0 {
+				fmt.Fprintf(&v.body, "%vif %v.Valid {\n%v%v}\n", indent, expr, inner.body.String(), indent)
+			}
+			return
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(t, "[]"):
+		if required {
+			fmt.Fprintf(&v.body, "%vif %v == nil {\n%v\terrs.Missing(%v)\n%v}\n", indent, expr, indent, path, indent)
+		}
+		inner := v.f.Validation()
+		index := fmt.Sprintf("%v(%v, i)", v.f.Import(gqlPackage+".Index"), path)
+		inner.check(t[2:], "e", index, typeName, nonNullElements, false, indent+"\t")
+		if inner.body.Len() > 0 {
+			fmt.Fprintf(&v.body, "%vfor i, e := range %v {\n%v%v}\n", indent, expr, inner.body.String(), indent)
+		}
+	case strings.HasPrefix(t, "*"):
+		inner := v.f.Validation()
+		inner.check(t[1:], expr, path, typeName, false, false, indent+"\t")
+		switch {
+		case required && inner.body.Len() > 0:
+			fmt.Fprintf(&v.body, "%vif %v == nil {\n%v\terrs.Missing(%v)\n%v} else {\n%v%v}\n", indent, expr, indent, path, indent, inner.body.String(), indent)
+		case required:
+			fmt.Fprintf(&v.body, "%vif %v == nil {\n%v\terrs.Missing(%v)\n%v}\n", indent, expr, indent, path, indent)
+		case inner.body.Len() > 0:
+			fmt.Fprintf(&v.body, "%vif %v != nil {\n%v%v}\n", indent, expr, inner.body.String(), indent)
+		}
+	case v.f.inputs[typeName] && t == v.f.TypeName(typeName):
+		fmt.Fprintf(&v.body, "%v%v.validate(%v, errs)\n", indent, expr, path)
+	}
+}
+
+// Fields writes the struct fields that the checks need into the struct s,
+// which is the set of fields it was decoded with.
+func (v *Validation) Fields(s *Struct) {
+	if len(v.required) == 0 {
+		return
+	}
+	s.add("fields", "the decoded fields")
+	fmt.Fprintf(v.f, "\tfields %v\n", v.f.Import(gqlPackage+".Fields"))
+}
+
+// Methods writes the Validate method of the struct name, which returns
+// every problem found as gql.Errors, and the validate method that nested
+// structs call with their path. Structs that need to know which fields
+// they were decoded with also get an UnmarshalJSON method.
+func (v *Validation) Methods(name string) {
+	errs := v.f.Import(gqlPackage + ".Errors")
+	fmt.Fprintf(v.f, "\nfunc (v %v) Validate() error {\n", name)
+	fmt.Fprintf(v.f, "\tvar errs %v\n\tv.validate(\"\", &errs)\n\treturn errs.Err()\n}\n", errs)
+	fmt.Fprintf(v.f, "\nfunc (v %v) validate(path string, errs *%v) {\n%v}\n", name, errs, v.body.String())
+	if len(v.required) == 0 {
+		return
+	}
+	args := []string{"d", fmt.Sprintf("%q", name), "(*plain)(v)"}
+	for _, r := range v.required {
+		args = append(args, fmt.Sprintf("%q", r))
+	}
+	fmt.Fprintf(v.f, "\nfunc (v *%v) UnmarshalJSON(d []byte) error {\n", name)
+	fmt.Fprintf(v.f, "\ttype plain %v\n", name)
+	fmt.Fprintf(v.f, "\tvar err error\n\tv.fields, err = %v(%v)\n\treturn err\n}\n", v.f.Import(gqlPackage+".Decode"), strings.Join(args, ", "))
+}
@@ -0,0 +1,32 @@
+package gqlast
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+func Run() {
+	schemaBytes, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read schema from stdin: %v\n", err)
+		os.Exit(1)
+	}
+	schema := string(schemaBytes)
+
+	p := parse.New(parse.NewLexer(schema))
+	rnode, perr := p.Parse()
+	if perr != nil {
+		fmt.Fprintf(os.Stderr, "failed to parse schema: %v (%v)\n", perr.Error, perr.Token)
+		os.Exit(1)
+	}
+
+	d, err := parse.MarshalNodeIndent(rnode, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to marshal ast: %v\n", err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(d)
+}
@@ -0,0 +1,200 @@
+// Package gqlcheck compares the resolvers a Go package implements with the
+// @resolve fields of a schema.
+package gqlcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/beauknowssoftware/go-gql-gen/internal/genresolvermanifest"
+	"github.com/beauknowssoftware/go-gql-gen/internal/gogen"
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+// Problem is a missing or orphaned resolver.
+type Problem struct {
+	// Pos is a schema position for missing resolvers and a Go position for
+	// orphaned ones.
+	Pos   string
+	Type  string
+	Field string
+	// Method is the Go method of an orphaned resolver, as Type.Method. It
+	// is empty for missing resolvers.
+	Method string
+}
+
+func (p Problem) String() string {
+	if p.Method == "" {
+		return fmt.Sprintf("%v: missing resolver for %v.%v", p.Pos, p.Type, p.Field)
+	}
+	return fmt.Sprintf("%v: orphaned resolver %v, %v has no @resolve field for it", p.Pos, p.Method, p.Type)
+}
+
+// loadPackage parses the Go files of dir that the go command would build,
+// leaving out tests and files excluded by build constraints, and type
+// checks them as far as their imports allow.
+func loadPackage(dir string) (*token.FileSet, *types.Package, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load Go package: %v", err)
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range append(bp.GoFiles, bp.CgoFiles...) {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse Go: %v", err)
+		}
+		files = append(files, f)
+	}
+	// Type errors, such as imports that cannot be found, are ignored since
+	// only the declarations of the package itself are needed.
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(error) {},
+	}
+	pkg, _ := conf.Check(bp.Name, fset, files, nil)
+	return fset, pkg, nil
+}
+
+// resolverType returns the schema type resolved by the named type name.
+// candidates maps the Go names of resolver types, like AccountResolver, to
+// their schema types. A type resolves a schema type when its name matches
+// ignoring case, like accountResolver, or when it implements the generated
+// interface of that name.
+func resolverType(scope *types.Scope, name string, candidates map[string]string) (string, bool) {
+	named, ok := scope.Lookup(name).Type().(*types.Named)
+	if !ok || types.IsInterface(named) {
+		return "", false
+	}
+	goNames := make([]string, 0, len(candidates))
+	for goName := range candidates {
+		if strings.EqualFold(name, goName) {
+			return candidates[goName], true
+		}
+		goNames = append(goNames, goName)
+	}
+	sort.Strings(goNames)
+	for _, goName := range goNames {
+		obj, ok := scope.Lookup(goName).(*types.TypeName)
+		if !ok || !types.IsInterface(obj.Type()) {
+			continue
+		}
+		if types.Implements(types.NewPointer(named), obj.Type().Underlying().(*types.Interface)) {
+			return candidates[goName], true
+		}
+	}
+	return "", false
+}
+
+type method struct {
+	name string
+	pos  token.Position
+	// own is set for methods declared on the resolver type itself, as
+	// opposed to those promoted from embedded fields.
+	own bool
+}
+
+// Check compares the resolvers of the Go package in dir with the @resolve
+// fields of the schema. files names the schema file of each definition.
+// Exported methods of resolver types are taken as resolvers of the field
+// with the same Go name. Promoted methods count as resolvers, but only the
+// methods declared on a resolver type itself are reported as orphaned, so
+// that embedding a type like sync.Mutex does not report its methods.
+func Check(dir string, rnode parse.Node, files []string, o gogen.Options) ([]Problem, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("failed to read Go package: %v", err)
+	}
+	fset, pkg, err := loadPackage(dir)
+	if err != nil {
+		return nil, err
+	}
+	f := gogen.NewFile(pkg.Name(), rnode, o)
+
+	// Every object type is a candidate, so that resolvers of types without
+	// @resolve fields are reported too.
+	candidates := make(map[string]string)
+	parse.Traverse(rnode, func(n parse.Node) bool {
+		if tdn, ok := n.(parse.TypeDefNode); ok {
+			if !tdn.Input {
+				candidates[f.TypeName(tdn.Name)+"Resolver"] = tdn.Name
+			}
+			return false
+		}
+		return true
+	})
+
+	// methods maps schema types to the methods of their resolver types,
+	// keyed by name.
+	methods := make(map[string]map[string]method)
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		if _, ok := scope.Lookup(name).(*types.TypeName); !ok {
+			continue
+		}
+		t, ok := resolverType(scope, name, candidates)
+		if !ok {
+			continue
+		}
+		if methods[t] == nil {
+			methods[t] = make(map[string]method)
+		}
+		named := scope.Lookup(name).Type().(*types.Named)
+		ms := types.NewMethodSet(types.NewPointer(named))
+		for i := 0; i < ms.Len(); i++ {
+			if m := ms.At(i); m.Obj().Exported() {
+				own := len(m.Index()) == 1 && m.Obj().Pkg() == pkg
+				methods[t][m.Obj().Name()] = method{name: name + "." + m.Obj().Name(), pos: fset.Position(m.Obj().Pos()), own: own}
+			}
+		}
+	}
+
+	var problems []Problem
+	resolved := make(map[string]map[string]bool)
+	for _, e := range genresolvermanifest.Manifest(rnode, files) {
+		goName := f.FieldName(e.Type, e.Field)
+		if resolved[e.Type] == nil {
+			resolved[e.Type] = make(map[string]bool)
+		}
+		resolved[e.Type][goName] = true
+		if _, ok := methods[e.Type][goName]; ok {
+			continue
+		}
+		pos := fmt.Sprintf("line %v", e.Source.Line)
+		if e.Source.File != "" {
+			pos = fmt.Sprintf("%v:%v", e.Source.File, e.Source.Line)
+		}
+		problems = append(problems, Problem{Pos: pos, Type: e.Type, Field: e.Field})
+	}
+
+	var orphans []method
+	orphanTypes := make(map[string]string)
+	for t, ms := range methods {
+		for goName, m := range ms {
+			if m.own && !resolved[t][goName] {
+				orphans = append(orphans, m)
+				orphanTypes[m.name] = t
+			}
+		}
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		a, b := orphans[i].pos, orphans[j].pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+	for _, m := range orphans {
+		problems = append(problems, Problem{Pos: m.pos.String(), Type: orphanTypes[m.name], Method: m.name})
+	}
+	return problems, nil
+}
@@ -0,0 +1,53 @@
+package gqlcheck_test
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/beauknowssoftware/go-gql-gen/internal/gogen"
+	"github.com/beauknowssoftware/go-gql-gen/internal/gqlcheck"
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+func TestCheck(t *testing.T) {
+	tests := map[string][]string{
+		"embedded": {
+			"testdata/embedded/resolvers.go:29:25: orphaned resolver accountResolver.Statement, Account has no @resolve field for it",
+		},
+		"interface": {
+			"line 4: missing resolver for Account.balance",
+			"testdata/interface/resolvers.go:20:15: orphaned resolver ledger.Audit, Account has no @resolve field for it",
+		},
+		"tags": {
+			"line 4: missing resolver for Account.balance",
+		},
+	}
+
+	d, err := ioutil.ReadFile("testdata/schema.graphqls")
+	if err != nil {
+		t.Fatalf("failed to read schema %v", err)
+	}
+	p := parse.New(parse.NewLexer(string(d)))
+	rnode, perr := p.Parse()
+	if perr != nil {
+		t.Fatalf("failed to parse schema %v", perr.Error)
+	}
+
+	for name, expected := range tests {
+		t.Run(name, func(t *testing.T) {
+			problems, err := gqlcheck.Check("testdata/"+name, rnode, nil, gogen.Options{})
+			if err != nil {
+				t.Fatalf("failed to check %v", err)
+			}
+			got := make([]string, len(problems))
+			for i, p := range problems {
+				got[i] = p.String()
+			}
+			if diff := cmp.Diff(expected, got); diff != "" {
+				t.Fatalf("mismatch (-expected,+got) %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package embedded
+
+import (
+	"context"
+	"sync"
+)
+
+type Account struct{}
+
+type User struct{}
+
+type owners struct{}
+
+func (owners) Owner(ctx context.Context, parent *Account) (*User, error) {
+	return nil, nil
+}
+
+// accountResolver gets Owner from owners and Lock and Unlock from
+// sync.Mutex, none of which are orphans.
+type accountResolver struct {
+	sync.Mutex
+	owners
+}
+
+func (*accountResolver) Balance(ctx context.Context, parent *Account) (*int, error) {
+	return nil, nil
+}
+
+func (*accountResolver) Statement(ctx context.Context, parent *Account) (*string, error) {
+	return nil, nil
+}
@@ -0,0 +1,29 @@
+package iface
+
+import "context"
+
+type Account struct{}
+
+type User struct{}
+
+type AccountResolver interface {
+	Owner(ctx context.Context, parent *Account) (*User, error)
+}
+
+// ledger only resolves Account because it implements AccountResolver.
+type ledger struct{}
+
+func (ledger) Owner(ctx context.Context, parent *Account) (*User, error) {
+	return nil, nil
+}
+
+func (ledger) Audit(ctx context.Context, parent *Account) error {
+	return nil
+}
+
+// cache implements nothing and is left alone.
+type cache struct{}
+
+func (cache) Balance() int {
+	return 0
+}
@@ -0,0 +1,9 @@
+//go:build never
+
+package tags
+
+import "context"
+
+func (accountResolver) Balance(ctx context.Context, parent *Account) (*int, error) {
+	return nil, nil
+}
@@ -0,0 +1,6 @@
+//go:build ignore
+
+// gen is a helper for go generate, excluded from the package.
+package main
+
+func main() {}
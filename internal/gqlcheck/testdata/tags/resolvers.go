@@ -0,0 +1,13 @@
+package tags
+
+import "context"
+
+type Account struct{}
+
+type User struct{}
+
+type accountResolver struct{}
+
+func (accountResolver) Owner(ctx context.Context, parent *Account) (*User, error) {
+	return nil, nil
+}
@@ -0,0 +1 @@
+package tags_test
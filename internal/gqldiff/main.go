@@ -0,0 +1,76 @@
+package gqldiff
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/diff"
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+var jsonFlag = flag.Bool("json", false, "print the changes as JSON")
+var failOnFlag = flag.String("fail-on", "", "exit with status 1 when a change is at least this critical (breaking, dangerous or safe)")
+
+func parseFile(filename string) parse.Node {
+	schemaBytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read schema: %v\n", err)
+		os.Exit(2)
+	}
+
+	p := parse.New(parse.NewLexer(string(schemaBytes)))
+	rnode, perr := p.Parse()
+	if perr != nil {
+		fmt.Fprintf(os.Stderr, "failed to parse schema %v: %v (%v)\n", filename, perr.Error, perr.Token)
+		os.Exit(2)
+	}
+	return rnode
+}
+
+func Run() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: gql-diff [flags] old.graphqls new.graphqls\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	failOn := diff.Breaking + 1
+	if *failOnFlag != "" {
+		c, err := diff.ParseCriticality(*failOnFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(2)
+		}
+		failOn = c
+	}
+
+	changes := diff.Diff(parseFile(flag.Arg(0)), parseFile(flag.Arg(1)))
+
+	if *jsonFlag {
+		d, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal changes: %v\n", err)
+			os.Exit(2)
+		}
+		os.Stdout.Write(d)
+		fmt.Println()
+	} else {
+		for _, c := range changes {
+			fmt.Println(c)
+		}
+	}
+
+	for _, c := range changes {
+		if c.Criticality >= failOn {
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package gqldiff_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os/exec"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func ReadFile(t *testing.T, filename string) string {
+	d, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read file %v", filename)
+	}
+	return string(d)
+}
+
+func Test_Main(t *testing.T) {
+	tests := map[string]struct {
+		args             []string
+		files            []string
+		expectedFile     string
+		expectedExitCode int
+	}{
+		"text": {
+			expectedFile: "changes.txt",
+		},
+		"json": {
+			args:         []string{"-json"},
+			expectedFile: "changes.json",
+		},
+		"json without changes": {
+			args:         []string{"-json"},
+			files:        []string{"testdata/old.graphqls", "testdata/old.graphqls"},
+			expectedFile: "none.json",
+		},
+		"fail on breaking": {
+			args:             []string{"-fail-on", "breaking"},
+			expectedFile:     "changes.txt",
+			expectedExitCode: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			files := test.files
+			if files == nil {
+				files = []string{"testdata/old.graphqls", "testdata/new.graphqls"}
+			}
+			args := append(test.args, files...)
+			cmd := exec.Command("gql-diff", args...)
+			var outBuff, errBuff bytes.Buffer
+			cmd.Stdout = &outBuff
+			cmd.Stderr = &errBuff
+
+			exitCode := 0
+			if err := cmd.Run(); err != nil {
+				exitErr, ok := err.(*exec.ExitError)
+				if !ok {
+					t.Fatalf("failed to run %v\n%v\n%v", err, outBuff.String(), errBuff.String())
+				}
+				exitCode = exitErr.ExitCode()
+			}
+			if exitCode != test.expectedExitCode {
+				t.Fatalf("expected exit code %v got %v\n%v", test.expectedExitCode, exitCode, errBuff.String())
+			}
+
+			expected := ReadFile(t, "testdata/"+test.expectedFile)
+			if diff := cmp.Diff(expected, outBuff.String()); diff != "" {
+				t.Fatalf("mismatch (-expected,+got) %v", diff)
+			}
+		})
+	}
+}
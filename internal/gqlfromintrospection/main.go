@@ -0,0 +1,32 @@
+package gqlfromintrospection
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/introspection"
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+func Run() {
+	resultBytes, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read introspection result from stdin: %v\n", err)
+		os.Exit(1)
+	}
+
+	schema, err := introspection.Decode(resultBytes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	doc, err := introspection.ToDocument(schema)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to convert introspection result: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Print(parse.Print(doc))
+}
@@ -0,0 +1,73 @@
+package gqlfromintrospection_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func OpenFile(t *testing.T, filename string) *os.File {
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatalf("failed to open file %v", filename)
+	}
+	return f
+}
+
+func ReadFile(t *testing.T, filename string) string {
+	d, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read file %v", filename)
+	}
+	return string(d)
+}
+
+func Test_Main(t *testing.T) {
+	tests := []string{
+		"types",
+	}
+
+	for _, name := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := OpenFile(t, "testdata/"+name+".json")
+
+			cmd := exec.Command("gql-from-introspection")
+			var outBuff, errBuff bytes.Buffer
+			cmd.Stdin = result
+			cmd.Stdout = &outBuff
+			cmd.Stderr = &errBuff
+
+			if err := cmd.Run(); err != nil {
+				t.Fatalf("failed to run %v\n%v\n%v", err, outBuff.String(), errBuff.String())
+			}
+
+			expected := ReadFile(t, "testdata/"+name+".graphqls")
+			if diff := cmp.Diff(expected, outBuff.String()); diff != "" {
+				t.Fatalf("mismatch (-expected,+got) %v", diff)
+			}
+		})
+	}
+}
+
+func Test_MainError(t *testing.T) {
+	result := OpenFile(t, "testdata/error.json")
+
+	cmd := exec.Command("gql-from-introspection")
+	var outBuff, errBuff bytes.Buffer
+	cmd.Stdin = result
+	cmd.Stdout = &outBuff
+	cmd.Stderr = &errBuff
+
+	if err := cmd.Run(); err == nil {
+		t.Fatalf("expected failure\n%v", outBuff.String())
+	}
+
+	expected := ReadFile(t, "testdata/error.txt")
+	if diff := cmp.Diff(expected, errBuff.String()); diff != "" {
+		t.Fatalf("mismatch (-expected,+got) %v", diff)
+	}
+}
@@ -0,0 +1,134 @@
+package gqlgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/beauknowssoftware/go-gql-gen/internal/gogen"
+)
+
+// DefaultConfig is read from the working directory when no -config flag is
+// given.
+const DefaultConfig = "gqlgen.json"
+
+// Config describes how a project is generated. Flags given on the command
+// line take precedence. Paths are relative to the directory of the config
+// file.
+type Config struct {
+	// Schema lists glob patterns of the schema files, which are combined
+	// into one schema.
+	Schema  stringList `json:"schema"`
+	Package string     `json:"package"`
+	// Scalars maps scalar names to Go types.
+	Scalars map[string]string `json:"scalars"`
+	// Types renames the Go types generated for schema types.
+	Types map[string]string `json:"types"`
+	// Nullable decides how nullable types map to Go: pointer, optional or
+	// value.
+	Nullable string `json:"nullable"`
+	// TriState wraps nullable input fields and arguments in gql.Input so
+	// that absent fields can be told from null ones.
+	TriState bool `json:"tristate"`
+	// Initialisms are written in upper case in Go names, on top of the ones
+	// golint knows.
+	Initialisms []string `json:"initialisms"`
+	// Fields renames Go fields, keyed by "Type.field" or
+	// "Type.field.argument".
+	Fields map[string]string `json:"fields"`
+	// Links sets the keys carried by the link of the @resolve field
+	// "Type.field", mapping key names to scalar types.
+	Links map[string]map[string]string `json:"links"`
+	// Generate lists the generators run by the generate command.
+	Generate map[string]Target `json:"generate"`
+}
+
+// Target is the configuration of one generator.
+type Target struct {
+	Output  string `json:"output"`
+	Package string `json:"package"`
+	// Format and DataSource configure the manifest generator, see its
+	// -format and -data-source flags.
+	Format     string `json:"format"`
+	DataSource string `json:"dataSource"`
+}
+
+// stringList accepts either a string or a list of strings.
+type stringList []string
+
+func (l *stringList) UnmarshalJSON(d []byte) error {
+	var s string
+	if err := json.Unmarshal(d, &s); err == nil {
+		*l = stringList{s}
+		return nil
+	}
+	return json.Unmarshal(d, (*[]string)(l))
+}
+
+func (c Config) goOptions() gogen.Options {
+	return gogen.Options{
+		Scalars:     c.Scalars,
+		Types:       c.Types,
+		Nullable:    c.Nullable,
+		TriState:    c.TriState,
+		Initialisms: c.Initialisms,
+		Fields:      c.Fields,
+		Links:       c.Links,
+	}
+}
+
+func readConfig(filename string) (Config, error) {
+	var c Config
+	d, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return c, fmt.Errorf("failed to read config: %v", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(d))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&c); err != nil {
+		return c, fmt.Errorf("failed to parse config %v: %v", filename, err)
+	}
+	if err := c.goOptions().Check(); err != nil {
+		return c, fmt.Errorf("invalid config %v: %v", filename, err)
+	}
+	for name := range c.Generate {
+		if cmd := findCommand(name); cmd == nil || !cmd.generator {
+			return c, fmt.Errorf("invalid config %v: unknown generator %q", filename, name)
+		}
+	}
+
+	dir := filepath.Dir(filename)
+	resolve := func(p string) string {
+		if p == "" || filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(dir, p)
+	}
+	for i, s := range c.Schema {
+		c.Schema[i] = resolve(s)
+	}
+	for name, t := range c.Generate {
+		t.Output = resolve(t.Output)
+		c.Generate[name] = t
+	}
+	return c, nil
+}
+
+func (o *options) loadConfig() error {
+	filename := o.config
+	if filename == "" {
+		if _, err := os.Stat(DefaultConfig); err != nil {
+			return nil
+		}
+		filename = DefaultConfig
+	}
+	c, err := readConfig(filename)
+	if err != nil {
+		return err
+	}
+	o.cfg = c
+	return nil
+}
@@ -0,0 +1,392 @@
+package gqlgen
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/beauknowssoftware/go-gql-gen/internal/genappsync"
+	"github.com/beauknowssoftware/go-gql-gen/internal/gengqlinputs"
+	"github.com/beauknowssoftware/go-gql-gen/internal/gengqltypes"
+	"github.com/beauknowssoftware/go-gql-gen/internal/genresolvermanifest"
+	"github.com/beauknowssoftware/go-gql-gen/internal/genresolvers"
+	"github.com/beauknowssoftware/go-gql-gen/internal/gqlcheck"
+	"github.com/beauknowssoftware/go-gql-gen/internal/gqltypes"
+)
+
+// usageError is reported for bad invocations, which exit with status 2
+// instead of 1.
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+type command struct {
+	name    string
+	summary string
+	// generator commands are run by the generate command.
+	generator bool
+	// flags registers the command specific flags and returns the function
+	// that runs the command.
+	flags func(fs *flag.FlagSet) func(o *options, w io.Writer) error
+}
+
+var commands = []*command{
+	{
+		name:      "types",
+		generator: true,
+		summary:   "generate Go types for the object types of a schema",
+		flags: func(fs *flag.FlagSet) func(o *options, w io.Writer) error {
+			return func(o *options, w io.Writer) error {
+				pkg, err := o.packageName()
+				if err != nil {
+					return err
+				}
+				rnode, err := o.parseSchema()
+				if err != nil {
+					return err
+				}
+				return gengqltypes.Generate(w, rnode, pkg, o.cfg.goOptions())
+			}
+		},
+	},
+	{
+		name:      "inputs",
+		generator: true,
+		summary:   "generate Go types for the inputs, objects and field arguments of a schema",
+		flags: func(fs *flag.FlagSet) func(o *options, w io.Writer) error {
+			triState := fs.Bool("tristate", false, "wrap nullable input fields and arguments in gql.Input to tell absent from null")
+			return func(o *options, w io.Writer) error {
+				pkg, err := o.packageName()
+				if err != nil {
+					return err
+				}
+				rnode, err := o.parseSchema()
+				if err != nil {
+					return err
+				}
+				opts := o.cfg.goOptions()
+				fs.Visit(func(f *flag.Flag) {
+					if f.Name == "tristate" {
+						opts.TriState = *triState
+					}
+				})
+				return gengqlinputs.Generate(w, rnode, pkg, opts)
+			}
+		},
+	},
+	{
+		name:      "resolvers",
+		generator: true,
+		summary:   "generate Go resolver interfaces for the @resolve fields of a schema",
+		flags: func(fs *flag.FlagSet) func(o *options, w io.Writer) error {
+			return func(o *options, w io.Writer) error {
+				pkg, err := o.packageName()
+				if err != nil {
+					return err
+				}
+				rnode, err := o.parseSchema()
+				if err != nil {
+					return err
+				}
+				return genresolvers.Generate(w, rnode, pkg, o.cfg.goOptions())
+			}
+		},
+	},
+	{
+		name:      "appsync",
+		generator: true,
+		summary:   "generate the AppSync Lambda dispatcher for the resolver interfaces",
+		flags: func(fs *flag.FlagSet) func(o *options, w io.Writer) error {
+			return func(o *options, w io.Writer) error {
+				pkg, err := o.packageName()
+				if err != nil {
+					return err
+				}
+				rnode, err := o.parseSchema()
+				if err != nil {
+					return err
+				}
+				return genappsync.Generate(w, rnode, pkg, o.cfg.goOptions())
+			}
+		},
+	},
+	{
+		name:      "manifest",
+		generator: true,
+		summary:   "generate the resolver manifest of a schema as JSON, CloudFormation, SAM or Terraform",
+		flags: func(fs *flag.FlagSet) func(o *options, w io.Writer) error {
+			format := fs.String("format", "", "output format: "+strings.Join(genresolvermanifest.Formats, ", ")+" (defaults to json)")
+			dataSource := fs.String("data-source", "", "data source of resolvers without @resolve(dataSource:)")
+			api := fs.String("api", "", "CloudFormation logical ID or Terraform name of the GraphQL API")
+			maxBatchSize := fs.Int("max-batch-size", genresolvermanifest.DefaultMaxBatchSize, "batch size of @resolve(batch: true) resolvers")
+			return func(o *options, w io.Writer) error {
+				mo := genresolvermanifest.Options{
+					Format:       o.target.Format,
+					DataSource:   o.target.DataSource,
+					API:          *api,
+					MaxBatchSize: *maxBatchSize,
+				}
+				if *format != "" {
+					mo.Format = *format
+				}
+				if *dataSource != "" {
+					mo.DataSource = *dataSource
+				}
+				if mo.Format != "" && !contains(genresolvermanifest.Formats, mo.Format) {
+					return usageError{fmt.Sprintf("unknown format %q", mo.Format)}
+				}
+				rnode, err := o.parseSchema()
+				if err != nil {
+					return err
+				}
+				return genresolvermanifest.Generate(w, rnode, o.files, mo)
+			}
+		},
+	},
+	{
+		name:    "check",
+		summary: "report @resolve fields without Go resolvers and Go resolvers without fields",
+		flags: func(fs *flag.FlagSet) func(o *options, w io.Writer) error {
+			dir := fs.String("dir", ".", "directory of the Go package holding the resolvers")
+			return func(o *options, w io.Writer) error {
+				rnode, err := o.parseSchema()
+				if err != nil {
+					return err
+				}
+				problems, err := gqlcheck.Check(*dir, rnode, o.files, o.cfg.goOptions())
+				if err != nil {
+					return err
+				}
+				if len(problems) == 0 {
+					return nil
+				}
+				// Problems go to stderr since a failed run writes no output.
+				missing := 0
+				for _, p := range problems {
+					fmt.Fprintln(os.Stderr, p)
+					if p.Method == "" {
+						missing++
+					}
+				}
+				return fmt.Errorf("%v missing and %v orphaned resolvers", missing, len(problems)-missing)
+			}
+		},
+	},
+	{
+		name:    "list",
+		summary: "list the types of a schema",
+		flags: func(fs *flag.FlagSet) func(o *options, w io.Writer) error {
+			sorted := fs.Bool("sort", false, "sort types by name instead of document order")
+			format := fs.String("format", "names", "output format: names, text, json or tsv")
+			kind := fs.String("kind", "object,interface,input", "comma separated kinds to list ("+strings.Join(gqltypes.Kinds, ", ")+" or all)")
+			directive := fs.String("directive", "", "only types or fields using this directive")
+			fieldType := fs.String("field-type", "", "only fields with this type or an argument of this type")
+			name := fs.String("name", "", "only types whose name matches this glob pattern")
+			return func(o *options, w io.Writer) error {
+				kinds, err := gqltypes.ParseKinds(*kind)
+				if err != nil {
+					return usageError{fmt.Sprintf("invalid -kind: %v", err)}
+				}
+				q := gqltypes.Query{
+					Kinds:     kinds,
+					Directive: strings.TrimPrefix(*directive, "@"),
+					FieldType: *fieldType,
+					Name:      *name,
+				}
+				if err := q.Check(); err != nil {
+					return usageError{err.Error()}
+				}
+				rnode, err := o.parseSchema()
+				if err != nil {
+					return err
+				}
+				return gqltypes.Write(w, q.Run(rnode, *sorted), *format)
+			}
+		},
+	},
+}
+
+// The generate command is added in init since it refers to commands.
+func init() {
+	commands = append([]*command{{
+		name:    "generate",
+		summary: "run every generator listed in the config",
+		flags: func(fs *flag.FlagSet) func(o *options, w io.Writer) error {
+			return generate
+		},
+	}}, commands...)
+}
+
+// generate runs the generators of the config in the order of commands,
+// writing each to its output.
+func generate(o *options, w io.Writer) error {
+	if len(o.cfg.Generate) == 0 {
+		return usageError{"no generators configured, see " + DefaultConfig}
+	}
+	if o.output != "" {
+		return usageError{"-o cannot be used with generate, outputs are set in the config"}
+	}
+	if _, err := o.parseSchema(); err != nil {
+		return err
+	}
+	for _, c := range generators() {
+		t, ok := o.cfg.Generate[c.name]
+		if !ok {
+			continue
+		}
+		if t.Output == "" {
+			return fmt.Errorf("%v: no output configured", c.name)
+		}
+		co := *o
+		co.target = t
+		_, run := c.flagSet(&options{})
+		var out bytes.Buffer
+		if err := run(&co, &out); err != nil {
+			return fmt.Errorf("%v: %v", c.name, err)
+		}
+		if err := co.writeOutput(out.Bytes()); err != nil {
+			return fmt.Errorf("%v: %v", c.name, err)
+		}
+	}
+	return nil
+}
+
+func generators() []*command {
+	var gs []*command
+	for _, c := range commands {
+		if c.generator {
+			gs = append(gs, c)
+		}
+	}
+	return gs
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+func findCommand(name string) *command {
+	for _, c := range commands {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, "usage: gqlgen <command> [flags]\n\ncommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-9v %v\n", c.name, c.summary)
+	}
+	fmt.Fprintf(w, "  %-9v %v\n", "help", "show the flags of a command")
+	fmt.Fprint(w, "\nrun \"gqlgen help <command>\" for the flags of a command.\n")
+}
+
+func (c *command) flagSet(o *options) (*flag.FlagSet, func(o *options, w io.Writer) error) {
+	fs := flag.NewFlagSet("gqlgen "+c.name, flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.Usage = func() {}
+	fs.StringVar(&o.schema, "schema", "", "read the schema from files matching this glob pattern instead of stdin")
+	fs.StringVar(&o.output, "o", "", "write the output to this file instead of stdout")
+	fs.StringVar(&o.pkg, "package", "", "Go package name of generated code (defaults to $GOPACKAGE)")
+	fs.StringVar(&o.config, "config", "", "read the project config from this file (defaults to "+DefaultConfig+" when present)")
+	return fs, c.flags(fs)
+}
+
+func (c *command) usage(w io.Writer) {
+	fs, _ := c.flagSet(&options{})
+	fs.SetOutput(w)
+	fmt.Fprintf(w, "usage: gqlgen %v [flags]\n\n%v\n\nflags:\n", c.name, c.summary)
+	fs.PrintDefaults()
+}
+
+// Main runs gqlgen with the given arguments and returns its exit status.
+func Main(args []string) int {
+	if len(args) == 0 {
+		usage(os.Stderr)
+		return 2
+	}
+
+	name := args[0]
+	switch name {
+	case "help", "-h", "-help", "--help":
+		if len(args) < 2 || name != "help" {
+			usage(os.Stdout)
+			return 0
+		}
+		c := findCommand(args[1])
+		if c == nil {
+			fmt.Fprintf(os.Stderr, "gqlgen help: unknown command %q\n", args[1])
+			return 2
+		}
+		c.usage(os.Stdout)
+		return 0
+	}
+
+	c := findCommand(name)
+	if c == nil {
+		fmt.Fprintf(os.Stderr, "gqlgen: unknown command %q\n\n", name)
+		usage(os.Stderr)
+		return 2
+	}
+
+	o := &options{}
+	fs, run := c.flagSet(o)
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			c.usage(os.Stdout)
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "gqlgen %v: %v\nrun \"gqlgen help %v\" for usage.\n", c.name, err, c.name)
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "gqlgen %v: unexpected arguments %v\n", c.name, strings.Join(fs.Args(), " "))
+		return 2
+	}
+
+	err := o.loadConfig()
+	o.target = o.cfg.Generate[c.name]
+	var out bytes.Buffer
+	if err == nil {
+		err = run(o, &out)
+	}
+	if err == nil {
+		err = o.writeOutput(out.Bytes())
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gqlgen %v: %v\n", c.name, err)
+		var uerr usageError
+		if errors.As(err, &uerr) {
+			return 2
+		}
+		return 1
+	}
+	return 0
+}
+
+func Run() {
+	os.Exit(Main(os.Args[1:]))
+}
+
+// Alias runs the command name with the arguments of the process. The
+// standalone binaries that predate gqlgen use it, so they take the same
+// flags and read the same config as their gqlgen command.
+func Alias(name string) {
+	os.Exit(Main(append([]string{name}, os.Args[1:]...)))
+}
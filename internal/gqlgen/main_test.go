@@ -0,0 +1,221 @@
+package gqlgen_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func OpenFile(t *testing.T, filename string) *os.File {
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatalf("failed to open file %v", filename)
+	}
+	return f
+}
+
+func ReadFile(t *testing.T, filename string) string {
+	d, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read file %v", filename)
+	}
+	return string(d)
+}
+
+func Test_Main(t *testing.T) {
+	tests := map[string]struct {
+		args  []string
+		stdin string
+		code  int
+	}{
+		"types":                    {args: []string{"types", "-package", "test", "-schema", "testdata/types.graphqls"}},
+		"inputs":                   {args: []string{"inputs", "-package", "test"}, stdin: "testdata/types.graphqls"},
+		"manifest":                 {args: []string{"manifest", "-schema", "testdata/types.graphqls"}},
+		"list":                     {args: []string{"list", "-sort", "-kind", "all", "-schema", "testdata/types.graphqls"}},
+		"config":                   {args: []string{"types", "-config", "testdata/gqlgen.json"}},
+		"config_override":          {args: []string{"types", "-config", "testdata/gqlgen.json", "-package", "test"}},
+		"nullable_pointer_types":   {args: []string{"types", "-config", "testdata/nullable_pointer.json"}},
+		"nullable_pointer_inputs":  {args: []string{"inputs", "-config", "testdata/nullable_pointer.json"}},
+		"nullable_optional_types":  {args: []string{"types", "-config", "testdata/nullable_optional.json"}},
+		"nullable_optional_inputs": {args: []string{"inputs", "-config", "testdata/nullable_optional.json"}},
+		"nullable_value_types":     {args: []string{"types", "-config", "testdata/nullable_value.json"}},
+		"nullable_value_inputs":    {args: []string{"inputs", "-config", "testdata/nullable_value.json"}},
+		"tristate_flag":            {args: []string{"inputs", "-tristate", "-config", "testdata/nullable_pointer.json"}},
+		"tristate_config":          {args: []string{"inputs", "-config", "testdata/tristate.json"}},
+		"tristate_flag_override":   {args: []string{"inputs", "-tristate=false", "-config", "testdata/tristate.json"}},
+		"naming_types":             {args: []string{"types", "-config", "testdata/naming.json"}},
+		"naming_inputs":            {args: []string{"inputs", "-config", "testdata/naming.json"}},
+		"links_inputs":             {args: []string{"inputs", "-config", "testdata/links.json"}},
+		"links_sibling":            {args: []string{"inputs", "-config", "testdata/links_sibling.json"}},
+		"links_default":            {args: []string{"inputs", "-package", "links", "-schema", "testdata/links.graphqls"}},
+		"links_mismatch":           {args: []string{"inputs", "-config", "testdata/links_mismatch.json"}, code: 1},
+		"directives_types":         {args: []string{"types", "-package", "test", "-schema", "testdata/directives.graphqls"}},
+		"directives_inputs":        {args: []string{"inputs", "-package", "test", "-schema", "testdata/directives.graphqls"}},
+		"lowercase_types":          {args: []string{"types", "-package", "test", "-schema", "testdata/lowercase.graphqls"}},
+		"lowercase_inputs":         {args: []string{"inputs", "-package", "test", "-schema", "testdata/lowercase.graphqls"}},
+		"lowercase_resolvers":      {args: []string{"resolvers", "-package", "test", "-schema", "testdata/lowercase.graphqls"}},
+		"lowercase_collision":      {args: []string{"types", "-package", "test", "-schema", "testdata/lowercase_collision.graphqls"}, code: 1},
+		"imports_types":            {args: []string{"types", "-package", "test", "-schema", "testdata/imports.graphqls"}},
+		"invalid_directive":        {args: []string{"types", "-package", "test", "-schema", "testdata/bad_directive.graphqls"}, code: 1},
+		"validate_inputs":          {args: []string{"inputs", "-package", "test", "-schema", "testdata/validate.graphqls"}},
+		"resolvers":                {args: []string{"resolvers", "-package", "test", "-schema", "testdata/types.graphqls"}},
+		"resolvers_roots":          {args: []string{"resolvers", "-package", "test", "-schema", "testdata/resolvers.graphqls"}},
+		"appsync":                  {args: []string{"appsync", "-package", "test", "-schema", "testdata/resolvers.graphqls"}},
+		"manifest_batch":           {args: []string{"manifest", "-schema", "testdata/resolvers.graphqls"}},
+		"manifest_cloudformation":  {args: []string{"manifest", "-format", "cloudformation", "-data-source", "lambda", "-schema", "testdata/types.graphqls"}},
+		"manifest_config":          {args: []string{"manifest", "-config", "testdata/manifest_terraform.json", "-o", "-"}},
+		"manifest_flags_override":  {args: []string{"manifest", "-config", "testdata/manifest_terraform.json", "-o", "-", "-format", "sam", "-api", "Api"}},
+		"manifest_no_data_source":  {args: []string{"manifest", "-format", "terraform", "-schema", "testdata/types.graphqls"}, code: 1},
+		"manifest_unknown_format":  {args: []string{"manifest", "-format", "xml", "-schema", "testdata/types.graphqls"}, code: 2},
+		"check":                    {args: []string{"check", "-schema", "testdata/check/schema.graphqls", "-dir", "testdata/check"}, code: 1},
+		"check_ok":                 {args: []string{"check", "-schema", "testdata/check/ok.graphqls", "-dir", "testdata/check"}},
+		"check_no_package":         {args: []string{"check", "-schema", "testdata/check/ok.graphqls", "-dir", "testdata/nothing"}, code: 1},
+		"field_collision":          {args: []string{"types", "-package", "test", "-schema", "testdata/collision.graphqls"}, code: 1},
+		"help":                     {args: []string{"help"}},
+		"help_list":                {args: []string{"help", "list"}},
+		"no_command":               {code: 2},
+		"unknown_command":          {args: []string{"build"}, code: 2},
+		"no_generators":            {args: []string{"generate", "-config", "testdata/gqlgen.json"}, code: 2},
+		"invalid_config":           {args: []string{"types", "-config", "testdata/invalid.json"}, code: 1},
+		"invalid_go":               {args: []string{"types", "-config", "testdata/badname.json"}, code: 1},
+		"project_types":            {args: []string{"types", "-config", "testdata/project/gqlgen.json", "-o", "-"}},
+		"unknown_flag":             {args: []string{"manifest", "-sort"}, code: 2},
+		"extra_args":               {args: []string{"manifest", "schema.graphqls"}, code: 2},
+		"no_package":               {args: []string{"types", "-schema", "testdata/types.graphqls"}, code: 2},
+		"missing_schema":           {args: []string{"manifest", "-schema", "testdata/missing.graphqls"}, code: 1},
+		"invalid_schema":           {args: []string{"manifest", "-schema", "testdata/invalid.graphqls"}, code: 1},
+		"bad_format":               {args: []string{"list", "-format", "xml", "-schema", "testdata/types.graphqls"}, code: 1},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cmd := exec.Command("gqlgen", test.args...)
+			for _, e := range os.Environ() {
+				if !strings.HasPrefix(e, "GOPACKAGE=") {
+					cmd.Env = append(cmd.Env, e)
+				}
+			}
+			if test.stdin != "" {
+				cmd.Stdin = OpenFile(t, test.stdin)
+			}
+			var outBuff, errBuff bytes.Buffer
+			cmd.Stdout = &outBuff
+			cmd.Stderr = &errBuff
+
+			err := cmd.Run()
+			code := 0
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				code = exitErr.ExitCode()
+			} else if err != nil {
+				t.Fatalf("failed to run %v", err)
+			}
+			if code != test.code {
+				t.Fatalf("expected exit code %v got %v\n%v\n%v", test.code, code, outBuff.String(), errBuff.String())
+			}
+
+			// Successful runs are checked against their output and failed
+			// runs against their diagnostics.
+			got := outBuff.String()
+			if code != 0 {
+				if got != "" {
+					t.Fatalf("expected no output on failure got %v", got)
+				}
+				got = errBuff.String()
+			}
+			expected := ReadFile(t, "testdata/"+name+".txt")
+			if diff := cmp.Diff(expected, got); diff != "" {
+				t.Fatalf("mismatch (-expected,+got) %v", diff)
+			}
+		})
+	}
+}
+
+func copyFile(t *testing.T, from, to string) {
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		t.Fatalf("failed to create dir %v", err)
+	}
+	if err := ioutil.WriteFile(to, []byte(ReadFile(t, from)), 0644); err != nil {
+		t.Fatalf("failed to write file %v", err)
+	}
+}
+
+func Test_Generate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gqlgen")
+	if err != nil {
+		t.Fatalf("failed to create temp dir %v", err)
+	}
+	defer os.RemoveAll(dir)
+	for _, f := range []string{"gqlgen.json", "schema/account.graphqls", "schema/query.graphqls"} {
+		copyFile(t, "testdata/project/"+f, filepath.Join(dir, f))
+	}
+
+	cmd := exec.Command("gqlgen", "generate")
+	cmd.Dir = dir
+	var outBuff, errBuff bytes.Buffer
+	cmd.Stdout = &outBuff
+	cmd.Stderr = &errBuff
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("failed to run %v\n%v\n%v", err, outBuff.String(), errBuff.String())
+	}
+
+	for _, f := range []string{"types_gen.go", "inputs_gen.go", "resolvers_gen.go", "manifest.json"} {
+		t.Run(f, func(t *testing.T) {
+			expected := ReadFile(t, "testdata/project/"+f+".test")
+			if diff := cmp.Diff(expected, ReadFile(t, filepath.Join(dir, f))); diff != "" {
+				t.Fatalf("mismatch (-expected,+got) %v", diff)
+			}
+		})
+	}
+}
+
+func Test_Output(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gqlgen")
+	if err != nil {
+		t.Fatalf("failed to create temp dir %v", err)
+	}
+	defer os.RemoveAll(dir)
+	out := dir + "/manifest.json"
+
+	cmd := exec.Command("gqlgen", "manifest", "-schema", "testdata/types.graphqls", "-o", out)
+	var outBuff, errBuff bytes.Buffer
+	cmd.Stdout = &outBuff
+	cmd.Stderr = &errBuff
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("failed to run %v\n%v", err, errBuff.String())
+	}
+	if outBuff.String() != "" {
+		t.Fatalf("expected no output on stdout got %v", outBuff.String())
+	}
+	if diff := cmp.Diff(ReadFile(t, "testdata/manifest.txt"), ReadFile(t, out)); diff != "" {
+		t.Fatalf("mismatch (-expected,+got) %v", diff)
+	}
+
+	// An unchanged output is not written again.
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(out, old, old); err != nil {
+		t.Fatalf("failed to set modification time %v", err)
+	}
+	cmd = exec.Command("gqlgen", "manifest", "-schema", "testdata/types.graphqls", "-o", out)
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("failed to run %v", err)
+	}
+	if info, err := os.Stat(out); err != nil || !info.ModTime().Equal(old) {
+		t.Fatalf("expected unchanged output to keep its modification time")
+	}
+
+	// A failed run leaves the existing output alone.
+	cmd = exec.Command("gqlgen", "manifest", "-schema", "testdata/invalid.graphqls", "-o", out)
+	if err := cmd.Run(); err == nil {
+		t.Fatalf("expected invalid schema to fail")
+	}
+	if diff := cmp.Diff(ReadFile(t, "testdata/manifest.txt"), ReadFile(t, out)); diff != "" {
+		t.Fatalf("mismatch (-expected,+got) %v", diff)
+	}
+}
@@ -0,0 +1,145 @@
+package gqlgen
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+// options are the flags shared by every command, along with the config
+// they fall back to.
+type options struct {
+	schema string
+	output string
+	pkg    string
+	config string
+	cfg    Config
+	// target is the config of the running generator.
+	target Target
+	rnode  parse.Node
+	// files names the schema file of each definition of rnode.
+	files []string
+}
+
+func (o *options) packageName() (string, error) {
+	for _, pkg := range []string{o.pkg, o.target.Package, o.cfg.Package} {
+		if pkg != "" {
+			return pkg, nil
+		}
+	}
+	if pkg, ok := os.LookupEnv("GOPACKAGE"); ok {
+		return pkg, nil
+	}
+	return "", usageError{"either GOPACKAGE environment variable or -package flag must be set"}
+}
+
+func (o *options) schemaFiles() ([]string, error) {
+	patterns := []string(o.cfg.Schema)
+	if o.schema != "" {
+		patterns = []string{o.schema}
+	}
+
+	var files []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		if pattern == "-" {
+			return nil, nil
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema pattern %q: %v", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("failed to read schema: no files match %v", pattern)
+		}
+		sort.Strings(matches)
+		for _, m := range matches {
+			if !seen[m] {
+				seen[m] = true
+				files = append(files, m)
+			}
+		}
+	}
+	return files, nil
+}
+
+func parseSchema(filename string, schemaBytes []byte) (parse.DocumentNode, error) {
+	p := parse.New(parse.NewLexer(string(schemaBytes)))
+	rnode, perr := p.Parse()
+	if perr != nil {
+		return parse.DocumentNode{}, fmt.Errorf("failed to parse schema %v: %v (%v)", filename, perr.Error, perr.Token)
+	}
+	doc, _ := rnode.(parse.DocumentNode)
+	return doc, nil
+}
+
+// parseSchema reads the schema files, or stdin when there are none, and
+// combines their definitions into one document.
+func (o *options) parseSchema() (parse.Node, error) {
+	if o.rnode != nil {
+		return o.rnode, nil
+	}
+
+	files, err := o.schemaFiles()
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		schemaBytes, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema: %v", err)
+		}
+		if o.rnode, err = parseSchema("stdin", schemaBytes); err != nil {
+			return nil, err
+		}
+		return o.rnode, nil
+	}
+
+	var combined parse.DocumentNode
+	for _, filename := range files {
+		schemaBytes, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema: %v", err)
+		}
+		doc, err := parseSchema(filename, schemaBytes)
+		if err != nil {
+			return nil, err
+		}
+		combined.Definitions = append(combined.Definitions, doc.Definitions...)
+		for range doc.Definitions {
+			o.files = append(o.files, filename)
+		}
+	}
+	o.rnode = combined
+	return o.rnode, nil
+}
+
+func (o *options) outputFile() string {
+	if o.output != "" {
+		return o.output
+	}
+	return o.target.Output
+}
+
+// writeOutput writes the output once the command has succeeded, so that a
+// failed run never truncates an existing file. A file that already holds
+// the output is left alone to keep its modification time.
+func (o *options) writeOutput(d []byte) error {
+	filename := o.outputFile()
+	if filename == "" || filename == "-" {
+		_, err := os.Stdout.Write(d)
+		return err
+	}
+	if existing, err := ioutil.ReadFile(filename); err == nil && bytes.Equal(existing, d) {
+		return nil
+	}
+	if err := ioutil.WriteFile(filename, d, 0644); err != nil {
+		return fmt.Errorf("failed to write output: %v", err)
+	}
+	return nil
+}
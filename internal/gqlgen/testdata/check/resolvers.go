@@ -0,0 +1,55 @@
+package check
+
+import "context"
+
+type Account struct {
+	ID ID
+}
+
+type User struct {
+	ID ID
+}
+
+type Transaction struct {
+	ID ID
+}
+
+type ID string
+
+type AccountTransactionsArgs struct {
+	First *int
+}
+
+type QueryAccountArgs struct {
+	ID ID
+}
+
+type accountResolver struct{}
+
+func (accountResolver) Owner(ctx context.Context, parent *Account) (*User, error) {
+	return &User{ID: "u1"}, nil
+}
+
+func (accountResolver) Transactions(ctx context.Context, parent *Account, args AccountTransactionsArgs) ([]Transaction, error) {
+	return nil, nil
+}
+
+// Statement resolved a field that was removed from the schema.
+func (accountResolver) Statement(ctx context.Context, parent *Account) (*string, error) {
+	return nil, nil
+}
+
+func (accountResolver) cache() {}
+
+// ledger resolves transactions by implementing TransactionResolver.
+type ledger struct{}
+
+func (*ledger) Account(ctx context.Context, parent *Transaction) (*Account, error) {
+	return nil, nil
+}
+
+type UserResolver struct{}
+
+func (UserResolver) Name(ctx context.Context, parent *User) (string, error) {
+	return "", nil
+}
@@ -0,0 +1,25 @@
+package check
+
+import (
+	"context"
+)
+
+type AccountResolver interface {
+	Owner(ctx context.Context, parent *Account) (*User, error)
+	Transactions(ctx context.Context, parent *Account, args AccountTransactionsArgs) ([]Transaction, error)
+	Balance(ctx context.Context, parent *Account) (*int, error)
+}
+
+type TransactionResolver interface {
+	Account(ctx context.Context, parent *Transaction) (*Account, error)
+}
+
+type QueryResolver interface {
+	Account(ctx context.Context, args QueryAccountArgs) (*Account, error)
+}
+
+type Resolver interface {
+	Account() AccountResolver
+	Transaction() TransactionResolver
+	Query() QueryResolver
+}
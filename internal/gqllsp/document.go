@@ -0,0 +1,509 @@
+package gqllsp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+	"github.com/beauknowssoftware/go-gql-gen/pkg/validate"
+)
+
+// builtinDirectiveDocs describes the directives that may be used without a
+// definition, most importantly the @resolve marker read by the generators.
+var builtinDirectiveDocs = map[string]string{
+	"deprecated":             "Marks an element of the schema as no longer supported.",
+	"specifiedBy":            "Exposes a URL that specifies the behaviour of a custom scalar.",
+	"resolve":                "Marks a field as resolved by a Lambda resolver. The field is listed in the resolver manifest and gets an arguments type.",
+	"goModel":                "Uses an existing Go type, given as model: \"import/path.Type\", instead of generating one.",
+	"goField":                "Sets the Go name or type of a field or argument with name: and type:.",
+	"aws_api_key":            "AppSync: allows access with an API key.",
+	"aws_iam":                "AppSync: allows access with IAM credentials.",
+	"aws_oidc":               "AppSync: allows access with an OpenID Connect token.",
+	"aws_lambda":             "AppSync: allows access through a Lambda authorizer.",
+	"aws_cognito_user_pools": "AppSync: allows access for Cognito user pool groups.",
+	"aws_auth":               "AppSync: restricts access to Cognito user pool groups.",
+	"aws_subscribe":          "AppSync: subscribes to the listed mutations.",
+}
+
+var definitionKeywords = map[string]int{
+	"type":      CompletionKindClass,
+	"interface": CompletionKindInterface,
+	"input":     CompletionKindStruct,
+	"enum":      CompletionKindEnum,
+	"scalar":    CompletionKindValue,
+	"union":     CompletionKindClass,
+}
+
+type document struct {
+	uri    string
+	parsed *parse.Document
+	lines  []string
+	tokens []parse.Token
+	root   *parse.DocumentNode
+	perr   *parse.Error
+	errs   []validate.Error
+}
+
+func splitLines(text string) []string {
+	text = strings.Replace(text, "\r\n", "\n", -1)
+	text = strings.Replace(text, "\r", "\n", -1)
+	return strings.Split(text, "\n")
+}
+
+func newDocument(uri string, text string) *document {
+	pd, perr := parse.ParseDocument(text)
+	return analyze(uri, pd, perr)
+}
+
+// change applies the changes of a didChange notification in order. Ranged
+// changes update the parsed document, which re-parses only the definitions
+// they touch.
+func (d *document) change(changes []TextDocumentContentChangeEvent) *document {
+	pd, perr := d.parsed, d.perr
+	lines := d.lines
+	for _, c := range changes {
+		if c.Range == nil {
+			pd, perr = parse.ParseDocument(c.Text)
+		} else {
+			perr = pd.Update(parse.Edit{Start: loc(lines, c.Range.Start), End: loc(lines, c.Range.End), Text: c.Text})
+		}
+		lines = splitLines(pd.Text())
+	}
+	return analyze(d.uri, pd, perr)
+}
+
+// analyze takes the tokens of a parsed document for navigation and
+// validates it.
+func analyze(uri string, pd *parse.Document, perr *parse.Error) *document {
+	d := &document{uri: uri, parsed: pd, lines: splitLines(pd.Text()), tokens: pd.Tokens()}
+
+	if perr != nil {
+		d.perr = perr
+		return d
+	}
+	root := pd.Root()
+	d.root = &root
+	d.errs = validate.Validate(root)
+	return d
+}
+
+// position converts a lexer location, which counts runes, into an LSP
+// position, which counts UTF-16 code units.
+func (d *document) position(loc parse.Loc) Position {
+	if loc.Line >= len(d.lines) {
+		return Position{loc.Line, loc.Column}
+	}
+	line := []rune(d.lines[loc.Line])
+	if loc.Column > len(line) {
+		return Position{loc.Line, loc.Column}
+	}
+	return Position{loc.Line, len(utf16.Encode(line[:loc.Column]))}
+}
+
+func (d *document) loc(pos Position) parse.Loc {
+	return loc(d.lines, pos)
+}
+
+// loc converts an LSP position into a lexer location in lines.
+func loc(lines []string, pos Position) parse.Loc {
+	if pos.Line >= len(lines) {
+		return parse.Loc{Line: pos.Line, Column: pos.Character}
+	}
+	column := 0
+	units := 0
+	for _, r := range lines[pos.Line] {
+		if units >= pos.Character {
+			break
+		}
+		units += len(utf16.Encode([]rune{r}))
+		column++
+	}
+	return parse.Loc{Line: pos.Line, Column: column}
+}
+
+func (d *document) tokenEnd(t parse.Token) parse.Loc {
+	end := t.Loc
+	switch t.TokenType {
+	case parse.TextToken, parse.IntToken, parse.FloatToken:
+		end.Column += len([]rune(t.Value))
+	case parse.StringToken:
+		end = d.stringEnd(t.Loc)
+	case parse.SpreadToken:
+		end.Column += 3
+	case parse.EOFToken:
+	default:
+		end.Column++
+	}
+	return end
+}
+
+// stringEnd finds the end of the string starting at start in the source,
+// as the token only holds its value with escapes resolved and block
+// strings may span lines.
+func (d *document) stringEnd(start parse.Loc) parse.Loc {
+	if start.Line >= len(d.lines) || start.Column > len([]rune(d.lines[start.Line])) {
+		return start
+	}
+	line := []rune(d.lines[start.Line])
+	if !strings.HasPrefix(string(line[start.Column:]), `"""`) {
+		for c := start.Column + 1; c < len(line); c++ {
+			switch line[c] {
+			case '\\':
+				c++
+			case '"':
+				return parse.Loc{Line: start.Line, Column: c + 1}
+			}
+		}
+		return parse.Loc{Line: start.Line, Column: len(line)}
+	}
+
+	end := parse.Loc{Line: start.Line, Column: start.Column + 3}
+	for ; end.Line < len(d.lines); end.Line, end.Column = end.Line+1, 0 {
+		line := []rune(d.lines[end.Line])
+		for ; end.Column < len(line); end.Column++ {
+			rest := string(line[end.Column:])
+			switch {
+			case strings.HasPrefix(rest, `\"""`):
+				end.Column += 3
+			case strings.HasPrefix(rest, `"""`):
+				end.Column += 3
+				return end
+			}
+		}
+	}
+	last := len(d.lines) - 1
+	return parse.Loc{Line: last, Column: len([]rune(d.lines[last]))}
+}
+
+func (d *document) tokenRange(t parse.Token) Range {
+	return Range{d.position(t.Loc), d.position(d.tokenEnd(t))}
+}
+
+func before(a parse.Loc, b parse.Loc) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+}
+
+// tokenIndex returns the index of the first token at or after loc.
+func (d *document) tokenIndex(loc parse.Loc) int {
+	return sort.Search(len(d.tokens), func(i int) bool {
+		return !before(d.tokens[i].Loc, loc)
+	})
+}
+
+// nameAt returns the index of the name token under loc, counting the
+// position just after the last character as part of the name.
+func (d *document) nameAt(loc parse.Loc) (int, bool) {
+	for i, t := range d.tokens {
+		if t.TokenType != parse.TextToken || t.Loc.Line != loc.Line {
+			continue
+		}
+		if t.Loc.Column <= loc.Column && loc.Column <= d.tokenEnd(t).Column {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (d *document) isDirectiveName(i int) bool {
+	return i > 0 && d.tokens[i-1].TokenType == parse.AtToken
+}
+
+// nthName returns the index of the nth name token at or after loc. The name
+// of a definition is the second name token: the first is its keyword.
+func (d *document) nthName(loc parse.Loc, nth int) (int, bool) {
+	for i := d.tokenIndex(loc); i < len(d.tokens); i++ {
+		if d.tokens[i].TokenType != parse.TextToken {
+			continue
+		}
+		if nth--; nth == 0 {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// span returns the range from start to the end of the last token before
+// limit.
+func (d *document) span(start parse.Loc, limit parse.Loc) Range {
+	end := start
+	if i := d.tokenIndex(limit) - 1; i >= 0 && !before(d.tokens[i].Loc, start) {
+		end = d.tokenEnd(d.tokens[i])
+	}
+	return Range{d.position(start), d.position(end)}
+}
+
+func (d *document) eof() parse.Loc {
+	if len(d.tokens) == 0 {
+		return parse.Loc{}
+	}
+	return d.tokens[len(d.tokens)-1].Loc
+}
+
+func definitionName(n parse.Node) string {
+	switch dn := n.(type) {
+	case parse.TypeDefNode:
+		return dn.Name
+	case parse.EnumDefNode:
+		return dn.Name
+	case parse.ScalarDefNode:
+		return dn.Name
+	case parse.UnionDefNode:
+		return dn.Name
+	}
+	return ""
+}
+
+func (d *document) typeDef(name string) parse.Node {
+	if d.root == nil {
+		return nil
+	}
+	for _, n := range d.root.Definitions {
+		if definitionName(n) == name {
+			return n
+		}
+	}
+	return nil
+}
+
+func (d *document) directiveDef(name string) parse.Node {
+	if d.root == nil {
+		return nil
+	}
+	for _, n := range d.root.Definitions {
+		if dn, ok := n.(parse.DirectiveDefNode); ok && dn.Name == name {
+			return n
+		}
+	}
+	return nil
+}
+
+func (d *document) diagnostics() []Diagnostic {
+	diagnostics := make([]Diagnostic, 0)
+	if d.perr != nil {
+		message := "failed to parse schema"
+		if d.perr.Error != nil {
+			message = d.perr.Error.Error()
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    d.tokenRange(d.perr.Token),
+			Severity: SeverityError,
+			Source:   "gql-lsp",
+			Message:  message,
+		})
+	}
+	for _, err := range d.errs {
+		r := Range{d.position(err.Loc), d.position(err.Loc)}
+		if i := d.tokenIndex(err.Loc); i < len(d.tokens) && d.tokens[i].Loc == err.Loc {
+			r = d.tokenRange(d.tokens[i])
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    r,
+			Severity: SeverityError,
+			Source:   "gql-lsp",
+			Message:  err.Message,
+		})
+	}
+	return diagnostics
+}
+
+func (d *document) definition(pos Position) *Location {
+	i, ok := d.nameAt(d.loc(pos))
+	if !ok {
+		return nil
+	}
+	name := d.tokens[i].Value
+
+	var def parse.Node
+	if d.isDirectiveName(i) {
+		def = d.directiveDef(name)
+	} else {
+		def = d.typeDef(name)
+	}
+	if def == nil {
+		return nil
+	}
+	j, ok := d.nthName(def.Loc(), 2)
+	if !ok {
+		return nil
+	}
+	return &Location{d.uri, d.tokenRange(d.tokens[j])}
+}
+
+func markdown(sdl string, doc string) MarkupContent {
+	value := fmt.Sprintf("```graphql\n%v```", sdl)
+	if doc != "" {
+		value += "\n\n" + doc
+	}
+	return MarkupContent{Kind: "markdown", Value: value}
+}
+
+func (d *document) hover(pos Position) *Hover {
+	i, ok := d.nameAt(d.loc(pos))
+	if !ok {
+		return nil
+	}
+	name := d.tokens[i].Value
+	r := d.tokenRange(d.tokens[i])
+
+	if d.isDirectiveName(i) {
+		if def := d.directiveDef(name); def != nil {
+			return &Hover{markdown(parse.Print(def), ""), &r}
+		}
+		if targets, ok := validate.BuiltinDirectives[name]; ok {
+			sdl := fmt.Sprintf("directive @%v on %v\n", name, strings.Join(targets, " | "))
+			return &Hover{markdown(sdl, builtinDirectiveDocs[name]), &r}
+		}
+		return nil
+	}
+
+	if def := d.typeDef(name); def != nil {
+		return &Hover{markdown(parse.Print(def), ""), &r}
+	}
+	for _, s := range validate.BuiltinScalars {
+		if s == name {
+			return &Hover{markdown(fmt.Sprintf("scalar %v\n", name), "Built in scalar."), &r}
+		}
+	}
+	return nil
+}
+
+// completion offers directive names after an @ and type names everywhere
+// else. Names are collected from the tokens so completion keeps working
+// while the document does not parse.
+func (d *document) completion(pos Position) CompletionList {
+	loc := d.loc(pos)
+	prefix := ""
+	if loc.Line < len(d.lines) {
+		line := []rune(d.lines[loc.Line])
+		if loc.Column <= len(line) {
+			prefix = strings.TrimRight(string(line[:loc.Column]), "_0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+		}
+	}
+
+	items := make([]CompletionItem, 0)
+	if strings.HasSuffix(prefix, "@") {
+		seen := make(map[string]bool)
+		for i, t := range d.tokens {
+			if t.TokenType == parse.TextToken && t.Value == "directive" && i+2 < len(d.tokens) &&
+				d.tokens[i+1].TokenType == parse.AtToken && d.tokens[i+2].TokenType == parse.TextToken {
+				name := d.tokens[i+2].Value
+				if !seen[name] {
+					seen[name] = true
+					items = append(items, CompletionItem{Label: name, Kind: CompletionKindFunction, Detail: "directive"})
+				}
+			}
+		}
+		for name := range validate.BuiltinDirectives {
+			if !seen[name] {
+				items = append(items, CompletionItem{Label: name, Kind: CompletionKindFunction, Detail: builtinDirectiveDocs[name]})
+			}
+		}
+	} else {
+		for _, name := range validate.BuiltinScalars {
+			items = append(items, CompletionItem{Label: name, Kind: CompletionKindValue, Detail: "scalar"})
+		}
+		seen := make(map[string]bool)
+		for i, t := range d.tokens {
+			kind, ok := definitionKeywords[t.Value]
+			if !ok || t.TokenType != parse.TextToken || i+1 >= len(d.tokens) || d.tokens[i+1].TokenType != parse.TextToken {
+				continue
+			}
+			name := d.tokens[i+1].Value
+			if !seen[name] {
+				seen[name] = true
+				items = append(items, CompletionItem{Label: name, Kind: kind, Detail: t.Value})
+			}
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Label < items[j].Label
+	})
+	return CompletionList{Items: items}
+}
+
+func (d *document) symbol(n parse.Node, limit parse.Loc, name string, nth int, kind int, detail string) DocumentSymbol {
+	s := DocumentSymbol{
+		Name:   name,
+		Detail: detail,
+		Kind:   kind,
+		Range:  d.span(n.Loc(), limit),
+	}
+	s.SelectionRange = s.Range
+	if i, ok := d.nthName(n.Loc(), nth); ok {
+		s.SelectionRange = d.tokenRange(d.tokens[i])
+	}
+	return s
+}
+
+// members returns symbols for the fields or enum values of a definition
+// that ends before limit.
+func (d *document) members(nodes []parse.Node, limit parse.Loc, kind int) []DocumentSymbol {
+	end := limit
+	if i := d.tokenIndex(limit) - 1; i >= 0 {
+		end = d.tokens[i].Loc
+	}
+
+	symbols := make([]DocumentSymbol, 0, len(nodes))
+	for i, n := range nodes {
+		next := end
+		if i+1 < len(nodes) {
+			next = nodes[i+1].Loc()
+		}
+		var name, detail string
+		switch mn := n.(type) {
+		case parse.FieldNode:
+			name = mn.Name
+			detail = parse.PrintType(mn.Type)
+		case parse.EnumValueNode:
+			name = mn.Name
+		}
+		symbols = append(symbols, d.symbol(n, next, name, 1, kind, detail))
+	}
+	return symbols
+}
+
+func (d *document) symbols() []DocumentSymbol {
+	symbols := make([]DocumentSymbol, 0)
+	if d.root == nil {
+		return symbols
+	}
+
+	definitions := d.root.Definitions
+	for i, n := range definitions {
+		limit := d.eof()
+		if i+1 < len(definitions) {
+			limit = definitions[i+1].Loc()
+		}
+
+		switch dn := n.(type) {
+		case parse.TypeDefNode:
+			kind, keyword := SymbolKindClass, "type"
+			if dn.Input {
+				kind, keyword = SymbolKindStruct, "input"
+			} else if dn.Interface {
+				kind, keyword = SymbolKindInterface, "interface"
+			}
+			s := d.symbol(dn, limit, dn.Name, 2, kind, keyword)
+			s.Children = d.members(dn.Fields, limit, SymbolKindField)
+			symbols = append(symbols, s)
+		case parse.EnumDefNode:
+			s := d.symbol(dn, limit, dn.Name, 2, SymbolKindEnum, "enum")
+			s.Children = d.members(dn.Values, limit, SymbolKindEnumMember)
+			symbols = append(symbols, s)
+		case parse.ScalarDefNode:
+			symbols = append(symbols, d.symbol(dn, limit, dn.Name, 2, SymbolKindTypeParameter, "scalar"))
+		case parse.UnionDefNode:
+			symbols = append(symbols, d.symbol(dn, limit, dn.Name, 2, SymbolKindEnum, "union"))
+		case parse.DirectiveDefNode:
+			symbols = append(symbols, d.symbol(dn, limit, "@"+dn.Name, 2, SymbolKindFunction, "directive"))
+		case parse.SchemaNode:
+			s := d.symbol(dn, limit, "schema", 1, SymbolKindModule, "")
+			s.Children = d.members(dn.Fields, limit, SymbolKindField)
+			symbols = append(symbols, s)
+		}
+	}
+	return symbols
+}
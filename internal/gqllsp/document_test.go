@@ -0,0 +1,45 @@
+package gqllsp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func largeSchema(types int) string {
+	var b strings.Builder
+	b.WriteString("interface Node {\n  id: ID!\n}\n\ndirective @key(fields: String!) on OBJECT\n\n")
+	for i := 0; i < types; i++ {
+		fmt.Fprintf(&b, "\"Type number %v\"\ntype Type%v implements Node @key(fields: \"id\") {\n", i, i)
+		b.WriteString("  id: ID!\n")
+		for j := 0; j < 10; j++ {
+			fmt.Fprintf(&b, "  field%v(first: Int = 10, after: String): [Type%v!]! @resolve\n", j, (i+j)%types)
+		}
+		b.WriteString("}\n\n")
+	}
+	return b.String()
+}
+
+// BenchmarkChangeKeystroke types and deletes a character in a field name in
+// the middle of the schema, like BenchmarkUpdateKeystroke in pkg/parse, but
+// through everything a didChange notification does.
+func BenchmarkChangeKeystroke(b *testing.B) {
+	d := newDocument("file:///schema.graphqls", largeSchema(1000))
+	if d.perr != nil || len(d.errs) > 0 {
+		b.Fatalf("failed to analyze %v %v", d.perr, d.errs)
+	}
+	at := Position{Line: 6 + 500*15 + 3, Character: 4}
+	after := Position{Line: at.Line, Character: at.Character + 1}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c := TextDocumentContentChangeEvent{Range: &Range{at, at}, Text: "x"}
+		if i%2 == 1 {
+			c = TextDocumentContentChangeEvent{Range: &Range{at, after}}
+		}
+		d = d.change([]TextDocumentContentChangeEvent{c})
+		if d.perr != nil {
+			b.Fatalf("failed to change %v", d.perr)
+		}
+	}
+}
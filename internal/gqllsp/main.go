@@ -0,0 +1,208 @@
+package gqllsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+type server struct {
+	out      io.Writer
+	docs     map[string]*document
+	shutdown bool
+	exited   bool
+}
+
+var errNoDocument = errors.New("document is not open")
+
+func decode(params json.RawMessage, v interface{}) *responseError {
+	if err := json.Unmarshal(params, v); err != nil {
+		return &responseError{invalidParams, fmt.Sprintf("invalid params: %v", err)}
+	}
+	return nil
+}
+
+func (s *server) publish(d *document) error {
+	return writeMessage(s.out, notification{
+		JSONRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params:  PublishDiagnosticsParams{d.uri, d.diagnostics()},
+	})
+}
+
+func (s *server) document(uri string) (*document, *responseError) {
+	d, ok := s.docs[uri]
+	if !ok {
+		return nil, &responseError{invalidParams, fmt.Sprintf("%v: %v", errNoDocument, uri)}
+	}
+	return d, nil
+}
+
+// notify handles messages without an id. Unknown notifications are ignored
+// as the protocol requires.
+func (s *server) notify(method string, params json.RawMessage) error {
+	switch method {
+	case "exit":
+		s.exited = true
+	case "textDocument/didOpen":
+		var p DidOpenTextDocumentParams
+		if err := decode(params, &p); err != nil {
+			return nil
+		}
+		d := newDocument(p.TextDocument.URI, p.TextDocument.Text)
+		s.docs[d.uri] = d
+		return s.publish(d)
+	case "textDocument/didChange":
+		var p DidChangeTextDocumentParams
+		if err := decode(params, &p); err != nil || len(p.ContentChanges) == 0 {
+			return nil
+		}
+		d, ok := s.docs[p.TextDocument.URI]
+		if !ok {
+			return nil
+		}
+		d = d.change(p.ContentChanges)
+		s.docs[d.uri] = d
+		return s.publish(d)
+	case "textDocument/didClose":
+		var p DidCloseTextDocumentParams
+		if err := decode(params, &p); err != nil {
+			return nil
+		}
+		delete(s.docs, p.TextDocument.URI)
+		return writeMessage(s.out, notification{
+			JSONRPC: "2.0",
+			Method:  "textDocument/publishDiagnostics",
+			Params:  PublishDiagnosticsParams{p.TextDocument.URI, []Diagnostic{}},
+		})
+	}
+	return nil
+}
+
+func (s *server) call(method string, params json.RawMessage) (interface{}, *responseError) {
+	switch method {
+	case "initialize":
+		return map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync":       2,
+				"hoverProvider":          true,
+				"definitionProvider":     true,
+				"documentSymbolProvider": true,
+				"completionProvider": map[string]interface{}{
+					"triggerCharacters": []string{"@"},
+				},
+			},
+			"serverInfo": map[string]string{"name": "gql-lsp"},
+		}, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/definition":
+		var p TextDocumentPositionParams
+		if err := decode(params, &p); err != nil {
+			return nil, err
+		}
+		d, err := s.document(p.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		if l := d.definition(p.Position); l != nil {
+			return l, nil
+		}
+		return nil, nil
+	case "textDocument/hover":
+		var p TextDocumentPositionParams
+		if err := decode(params, &p); err != nil {
+			return nil, err
+		}
+		d, err := s.document(p.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		if h := d.hover(p.Position); h != nil {
+			return h, nil
+		}
+		return nil, nil
+	case "textDocument/completion":
+		var p TextDocumentPositionParams
+		if err := decode(params, &p); err != nil {
+			return nil, err
+		}
+		d, err := s.document(p.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		return d.completion(p.Position), nil
+	case "textDocument/documentSymbol":
+		var p DocumentSymbolParams
+		if err := decode(params, &p); err != nil {
+			return nil, err
+		}
+		d, err := s.document(p.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		return d.symbols(), nil
+	}
+	return nil, &responseError{methodNotFound, fmt.Sprintf("method %v is not supported", method)}
+}
+
+func (s *server) respond(id json.RawMessage, result interface{}, rerr *responseError) error {
+	r := response{JSONRPC: "2.0", ID: id, Error: rerr}
+	if rerr == nil {
+		b, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to marshal result: %v", err)
+		}
+		r.Result = b
+	}
+	return writeMessage(s.out, r)
+}
+
+// Serve speaks the language server protocol over in and out until the
+// client sends exit or closes in. It returns an error if the client exits
+// without asking the server to shut down first.
+func Serve(in io.Reader, out io.Writer) error {
+	s := server{out: out, docs: make(map[string]*document)}
+	r := bufio.NewReader(in)
+
+	for !s.exited {
+		content, err := readMessage(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		// A message that is not JSON has no id to answer to, so the error
+		// goes back with a null one and the server carries on.
+		var req request
+		if err := json.Unmarshal(content, &req); err != nil {
+			err = s.respond(json.RawMessage("null"), nil, &responseError{parseError, fmt.Sprintf("failed to decode message: %v", err)})
+		} else if len(req.ID) == 0 {
+			err = s.notify(req.Method, req.Params)
+		} else {
+			result, rerr := s.call(req.Method, req.Params)
+			err = s.respond(req.ID, result, rerr)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write message: %v", err)
+		}
+	}
+
+	if !s.shutdown {
+		return errors.New("client exited without shutting down the server")
+	}
+	return nil
+}
+
+func Run() {
+	if err := Serve(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "gql-lsp: %v\n", err)
+		os.Exit(1)
+	}
+}
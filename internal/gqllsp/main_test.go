@@ -0,0 +1,509 @@
+package gqllsp_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/beauknowssoftware/go-gql-gen/internal/gqllsp"
+)
+
+const uri = "file:///schema.graphqls"
+
+func ReadFile(t *testing.T, filename string) string {
+	d, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read file %v", filename)
+	}
+	return string(d)
+}
+
+type message struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// client stands in for an editor, talking to a server running in process.
+type client struct {
+	t      *testing.T
+	w      io.WriteCloser
+	r      *bufio.Reader
+	id     int
+	served chan error
+}
+
+func newClient(t *testing.T) *client {
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+
+	c := &client{t: t, w: clientW, r: bufio.NewReader(clientR), served: make(chan error, 1)}
+	go func() {
+		err := gqllsp.Serve(serverR, serverW)
+		serverW.Close()
+		c.served <- err
+	}()
+	return c
+}
+
+func (c *client) send(v interface{}) {
+	content, err := json.Marshal(v)
+	if err != nil {
+		c.t.Fatalf("failed to marshal message %v", err)
+	}
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %v\r\n\r\n%s", len(content), content); err != nil {
+		c.t.Fatalf("failed to send message %v", err)
+	}
+}
+
+func (c *client) receive() message {
+	header, err := textproto.NewReader(c.r).ReadMIMEHeader()
+	if err != nil {
+		c.t.Fatalf("failed to read header %v", err)
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		c.t.Fatalf("invalid Content-Length %v", err)
+	}
+	content := make([]byte, length)
+	if _, err := io.ReadFull(c.r, content); err != nil {
+		c.t.Fatalf("failed to read content %v", err)
+	}
+	var m message
+	if err := json.Unmarshal(content, &m); err != nil {
+		c.t.Fatalf("failed to decode %s: %v", content, err)
+	}
+	return m
+}
+
+func (c *client) notify(method string, params interface{}) {
+	c.send(map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+func (c *client) call(method string, params interface{}, result interface{}) {
+	c.id++
+	c.send(map[string]interface{}{"jsonrpc": "2.0", "id": c.id, "method": method, "params": params})
+
+	m := c.receive()
+	if m.ID == nil || *m.ID != c.id {
+		c.t.Fatalf("expected response to %v got %+v", method, m)
+	}
+	if m.Error != nil {
+		c.t.Fatalf("%v failed: %v", method, m.Error.Message)
+	}
+	if err := json.Unmarshal(m.Result, result); err != nil {
+		c.t.Fatalf("failed to decode %v result %s: %v", method, m.Result, err)
+	}
+}
+
+func (c *client) diagnostics() []gqllsp.Diagnostic {
+	m := c.receive()
+	if m.Method != "textDocument/publishDiagnostics" {
+		c.t.Fatalf("expected diagnostics got %+v", m)
+	}
+	var p gqllsp.PublishDiagnosticsParams
+	if err := json.Unmarshal(m.Params, &p); err != nil {
+		c.t.Fatalf("failed to decode diagnostics %v", err)
+	}
+	return p.Diagnostics
+}
+
+func (c *client) open(text string) []gqllsp.Diagnostic {
+	c.notify("textDocument/didOpen", gqllsp.DidOpenTextDocumentParams{
+		TextDocument: gqllsp.TextDocumentItem{URI: uri, LanguageID: "graphql", Version: 1, Text: text},
+	})
+	return c.diagnostics()
+}
+
+func (c *client) change(text string) []gqllsp.Diagnostic {
+	c.notify("textDocument/didChange", gqllsp.DidChangeTextDocumentParams{
+		TextDocument:   gqllsp.TextDocumentIdentifier{URI: uri},
+		ContentChanges: []gqllsp.TextDocumentContentChangeEvent{{Text: text}},
+	})
+	return c.diagnostics()
+}
+
+func (c *client) edit(r gqllsp.Range, text string) []gqllsp.Diagnostic {
+	c.notify("textDocument/didChange", gqllsp.DidChangeTextDocumentParams{
+		TextDocument:   gqllsp.TextDocumentIdentifier{URI: uri},
+		ContentChanges: []gqllsp.TextDocumentContentChangeEvent{{Range: &r, Text: text}},
+	})
+	return c.diagnostics()
+}
+
+func (c *client) close() {
+	var result interface{}
+	c.call("shutdown", nil, &result)
+	c.notify("exit", nil)
+	if err := <-c.served; err != nil {
+		c.t.Fatalf("server failed %v", err)
+	}
+}
+
+func at(line int, character int) gqllsp.TextDocumentPositionParams {
+	return gqllsp.TextDocumentPositionParams{
+		TextDocument: gqllsp.TextDocumentIdentifier{URI: uri},
+		Position:     gqllsp.Position{Line: line, Character: character},
+	}
+}
+
+func rng(line int, start int, end int) gqllsp.Range {
+	return gqllsp.Range{
+		Start: gqllsp.Position{Line: line, Character: start},
+		End:   gqllsp.Position{Line: line, Character: end},
+	}
+}
+
+func newSession(t *testing.T) *client {
+	c := newClient(t)
+
+	var result struct {
+		Capabilities map[string]interface{} `json:"capabilities"`
+	}
+	c.call("initialize", map[string]interface{}{"capabilities": map[string]interface{}{}}, &result)
+	for _, capability := range []string{"hoverProvider", "definitionProvider", "documentSymbolProvider", "completionProvider"} {
+		if result.Capabilities[capability] == nil {
+			t.Fatalf("expected capability %v got %v", capability, result.Capabilities)
+		}
+	}
+	c.notify("initialized", map[string]interface{}{})
+
+	if diagnostics := c.open(ReadFile(t, "testdata/schema.graphqls")); len(diagnostics) > 0 {
+		t.Fatalf("expected no diagnostics got %v", diagnostics)
+	}
+	return c
+}
+
+func TestDiagnostics(t *testing.T) {
+	tests := map[string]struct {
+		text     string
+		expected []gqllsp.Diagnostic
+	}{
+		"parse error": {
+			text: "type Query {\n  a: String\n  b String\n}\n",
+			expected: []gqllsp.Diagnostic{
+				{
+					Range:    rng(2, 4, 10),
+					Severity: gqllsp.SeverityError,
+					Source:   "gql-lsp",
+					Message:  "expected colon token got text token",
+				},
+			},
+		},
+		"validation error": {
+			text: "type Query {\n  a: Missing\n}\n",
+			expected: []gqllsp.Diagnostic{
+				{
+					Range:    rng(1, 5, 12),
+					Severity: gqllsp.SeverityError,
+					Source:   "gql-lsp",
+					Message:  "field Query.a has unknown type Missing",
+				},
+			},
+		},
+		"escaped string": {
+			text: "type Query {\n  a: \"a\\\"b\" x\n}\n",
+			expected: []gqllsp.Diagnostic{
+				{
+					Range:    rng(1, 5, 11),
+					Severity: gqllsp.SeverityError,
+					Source:   "gql-lsp",
+					Message:  "expected text token got string token",
+				},
+			},
+		},
+		"block string": {
+			text: "type Query {\n  a: \"\"\"x\ny\"\"\" x\n}\n",
+			expected: []gqllsp.Diagnostic{
+				{
+					Range:    gqllsp.Range{Start: gqllsp.Position{Line: 1, Character: 5}, End: gqllsp.Position{Line: 2, Character: 4}},
+					Severity: gqllsp.SeverityError,
+					Source:   "gql-lsp",
+					Message:  "expected text token got string token",
+				},
+			},
+		},
+		"fixed": {
+			text:     "type Query {\n  a: String\n}\n",
+			expected: []gqllsp.Diagnostic{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newSession(t)
+			defer c.close()
+
+			if diff := cmp.Diff(test.expected, c.change(test.text)); diff != "" {
+				t.Fatalf("mismatch (expected, got) %v", diff)
+			}
+		})
+	}
+}
+
+func TestIncrementalChange(t *testing.T) {
+	c := newClient(t)
+	defer c.close()
+	c.open("type Query {\n  a: String\n}\n")
+
+	diagnostics := c.edit(rng(1, 5, 11), "Missing")
+	expected := []gqllsp.Diagnostic{
+		{
+			Range:    rng(1, 5, 12),
+			Severity: gqllsp.SeverityError,
+			Source:   "gql-lsp",
+			Message:  "field Query.a has unknown type Missing",
+		},
+	}
+	if diff := cmp.Diff(expected, diagnostics); diff != "" {
+		t.Fatalf("mismatch (expected, got) %v", diff)
+	}
+
+	// A comment opened before the next definition on the same line hides
+	// it, so the whole document is parsed again.
+	c.edit(rng(2, 1, 1), " scalar Missing")
+	if diagnostics := c.edit(rng(2, 1, 1), " #"); len(diagnostics) != 1 {
+		t.Fatalf("expected the commented out scalar to be missing got %v", diagnostics)
+	}
+	if diagnostics := c.edit(rng(2, 1, 3), ""); len(diagnostics) != 0 {
+		t.Fatalf("expected no diagnostics got %v", diagnostics)
+	}
+}
+
+func TestMalformedMessage(t *testing.T) {
+	c := newClient(t)
+	defer c.close()
+
+	if _, err := fmt.Fprintf(c.w, "Content-Length: 5\r\n\r\n{nope"); err != nil {
+		t.Fatalf("failed to send message %v", err)
+	}
+	m := c.receive()
+	if m.Error == nil || m.Error.Code != -32700 {
+		t.Fatalf("expected a parse error got %+v", m)
+	}
+
+	// The server keeps serving.
+	var result interface{}
+	c.call("initialize", map[string]interface{}{"capabilities": map[string]interface{}{}}, &result)
+}
+
+func TestDefinition(t *testing.T) {
+	tests := map[string]struct {
+		position gqllsp.TextDocumentPositionParams
+		expected *gqllsp.Location
+	}{
+		"type in list": {
+			position: at(6, 11),
+			expected: &gqllsp.Location{URI: uri, Range: rng(9, 5, 9)},
+		},
+		"end of type name": {
+			position: at(11, 14),
+			expected: &gqllsp.Location{URI: uri, Range: rng(3, 5, 9)},
+		},
+		"directive": {
+			position: at(5, 18),
+			expected: &gqllsp.Location{URI: uri, Range: rng(0, 11, 15)},
+		},
+		"schema operation": {
+			position: at(24, 10),
+			expected: &gqllsp.Location{URI: uri, Range: rng(19, 5, 10)},
+		},
+		"builtin scalar": {
+			position: at(4, 7),
+		},
+		"whitespace": {
+			position: at(7, 0),
+		},
+	}
+
+	c := newSession(t)
+	defer c.close()
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var location *gqllsp.Location
+			c.call("textDocument/definition", test.position, &location)
+
+			if diff := cmp.Diff(test.expected, location); diff != "" {
+				t.Fatalf("mismatch (expected, got) %v", diff)
+			}
+		})
+	}
+}
+
+func TestHover(t *testing.T) {
+	tests := map[string]struct {
+		position gqllsp.TextDocumentPositionParams
+		expected string
+	}{
+		"type": {
+			position: at(11, 10),
+			expected: "```graphql\n" +
+				"\"A user of the system\"\n" +
+				"type User {\n" +
+				"  id: ID!\n" +
+				"  name: String @auth(role: \"admin\")\n" +
+				"  posts: [Post!]! @resolve\n" +
+				"}\n" +
+				"```",
+		},
+		"enum": {
+			position: at(14, 6),
+			expected: "```graphql\nenum Role {\n  ADMIN\n  USER\n}\n```",
+		},
+		"builtin scalar": {
+			position: at(4, 7),
+			expected: "```graphql\nscalar ID\n```\n\nBuilt in scalar.",
+		},
+		"defined directive": {
+			position: at(5, 17),
+			expected: "```graphql\ndirective @auth(role: String) on FIELD_DEFINITION\n```",
+		},
+		"resolve directive": {
+			position: at(6, 20),
+			expected: "```graphql\ndirective @resolve on FIELD_DEFINITION\n```\n\n" +
+				"Marks a field as resolved by a Lambda resolver. The field is listed in the resolver manifest and gets an arguments type.",
+		},
+	}
+
+	c := newSession(t)
+	defer c.close()
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var hover *gqllsp.Hover
+			c.call("textDocument/hover", test.position, &hover)
+			if hover == nil {
+				t.Fatal("expected hover got none")
+			}
+
+			if diff := cmp.Diff(test.expected, hover.Contents.Value); diff != "" {
+				t.Fatalf("mismatch (expected, got) %v", diff)
+			}
+		})
+	}
+}
+
+func labels(list gqllsp.CompletionList) []string {
+	ls := make([]string, len(list.Items))
+	for i, item := range list.Items {
+		ls[i] = item.Label
+	}
+	return ls
+}
+
+func TestCompletion(t *testing.T) {
+	tests := map[string]struct {
+		text     string
+		position gqllsp.TextDocumentPositionParams
+		expected []string
+	}{
+		"types": {
+			text:     "type A {\n  b: \n}\nenum C { D }\ninput E { f: Int }\n",
+			position: at(1, 5),
+			expected: []string{"A", "Boolean", "C", "E", "Float", "ID", "Int", "String"},
+		},
+		"types while typing": {
+			text:     "type A {\n  b: St\n}\n",
+			position: at(1, 7),
+			expected: []string{"A", "Boolean", "Float", "ID", "Int", "String"},
+		},
+		"directives": {
+			text:     "directive @auth on FIELD_DEFINITION\ntype A {\n  b: String @\n}\n",
+			position: at(2, 13),
+			expected: []string{
+				"auth",
+				"aws_api_key",
+				"aws_auth",
+				"aws_cognito_user_pools",
+				"aws_iam",
+				"aws_lambda",
+				"aws_oidc",
+				"aws_subscribe",
+				"deprecated",
+				"goField",
+				"goModel",
+				"resolve",
+				"specifiedBy",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newSession(t)
+			defer c.close()
+			c.change(test.text)
+
+			var list gqllsp.CompletionList
+			c.call("textDocument/completion", test.position, &list)
+
+			if diff := cmp.Diff(test.expected, labels(list)); diff != "" {
+				t.Fatalf("mismatch (expected, got) %v", diff)
+			}
+		})
+	}
+}
+
+func TestDocumentSymbols(t *testing.T) {
+	c := newSession(t)
+	defer c.close()
+
+	var symbols []gqllsp.DocumentSymbol
+	c.call("textDocument/documentSymbol", gqllsp.DocumentSymbolParams{
+		TextDocument: gqllsp.TextDocumentIdentifier{URI: uri},
+	}, &symbols)
+
+	var got []string
+	var walk func(indent string, symbols []gqllsp.DocumentSymbol)
+	walk = func(indent string, symbols []gqllsp.DocumentSymbol) {
+		for _, s := range symbols {
+			r := s.Range
+			got = append(got, fmt.Sprintf("%v%v %v (%v) %v:%v-%v:%v", indent, s.Name, s.Detail, s.Kind, r.Start.Line, r.Start.Character, r.End.Line, r.End.Character))
+			walk(indent+"  ", s.Children)
+		}
+	}
+	walk("", symbols)
+
+	expected := strings.Join([]string{
+		"@auth directive (12) 0:0-0:49",
+		"User type (5) 2:0-7:1",
+		"  id ID! (8) 4:2-4:9",
+		"  name String (8) 5:2-5:35",
+		"  posts [Post!]! (8) 6:2-6:26",
+		"Post type (5) 9:0-12:1",
+		"  id ID! (8) 10:2-10:9",
+		"  author User (8) 11:2-11:14",
+		"Role enum (10) 14:0-17:1",
+		"  ADMIN  (22) 15:2-15:7",
+		"  USER  (22) 16:2-16:6",
+		"Query type (5) 19:0-21:1",
+		"  user User (8) 20:2-20:30",
+		"schema  (2) 23:0-25:1",
+		"  query Query (8) 24:2-24:14",
+	}, "\n")
+	if diff := cmp.Diff(expected, strings.Join(got, "\n")); diff != "" {
+		t.Fatalf("mismatch (expected, got) %v", diff)
+	}
+}
+
+func TestExitWithoutShutdown(t *testing.T) {
+	c := newClient(t)
+	c.notify("exit", nil)
+	if err := <-c.served; err == nil {
+		t.Fatal("expected an error exiting without shutdown")
+	}
+}
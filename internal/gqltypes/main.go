@@ -0,0 +1,92 @@
+package gqltypes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseKinds parses a comma separated list of kinds. "all" selects every
+// kind.
+func ParseKinds(s string) ([]string, error) {
+	if s == "all" {
+		return nil, nil
+	}
+	kinds := strings.Split(s, ",")
+	for i, k := range kinds {
+		kinds[i] = strings.TrimSpace(k)
+		known := false
+		for _, kind := range Kinds {
+			if kind == kinds[i] {
+				known = true
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown kind %q", kinds[i])
+		}
+	}
+	return kinds, nil
+}
+
+func writeNames(w io.Writer, types []Type) {
+	for _, t := range types {
+		fmt.Fprintln(w, t.Name)
+	}
+}
+
+func withDirectives(s string, directives []string) string {
+	if len(directives) == 0 {
+		return s
+	}
+	return s + " " + strings.Join(directives, " ")
+}
+
+func writeText(w io.Writer, types []Type) {
+	for _, t := range types {
+		header := fmt.Sprintf("%v %v", t.Kind, t.Name)
+		if len(t.Interfaces) > 0 {
+			header += " implements " + strings.Join(t.Interfaces, " & ")
+		}
+		if len(t.Members) > 0 {
+			header += " = " + strings.Join(t.Members, " | ")
+		}
+		fmt.Fprintf(w, "%v %v\n", withDirectives(header, t.Directives), t.Loc)
+		for _, f := range append(t.Fields, t.Values...) {
+			fmt.Fprintf(w, "  %v %v\n", withDirectives(f.Signature(), f.Directives), f.Loc)
+		}
+	}
+}
+
+func writeTSV(w io.Writer, types []Type) {
+	fmt.Fprintln(w, "kind\ttype\tfield\tfield_type\targuments\tdirectives\tline\tcolumn")
+	for _, t := range types {
+		fmt.Fprintf(w, "%v\t%v\t\t\t\t%v\t%v\t%v\n", t.Kind, t.Name, strings.Join(t.Directives, " "), t.Loc.Line, t.Loc.Column)
+		for _, f := range append(t.Fields, t.Values...) {
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n", t.Kind, t.Name, f.Name, f.Type, strings.Join(f.Arguments, ", "), strings.Join(f.Directives, " "), f.Loc.Line, f.Loc.Column)
+		}
+	}
+}
+
+// Write writes the types to w in the given format: names, text, json or
+// tsv.
+func Write(w io.Writer, types []Type, format string) error {
+	switch format {
+	case "names":
+		writeNames(w, types)
+	case "text":
+		writeText(w, types)
+	case "tsv":
+		writeTSV(w, types)
+	case "json":
+		d, err := json.MarshalIndent(types, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal types: %v", err)
+		}
+		w.Write(d)
+		fmt.Fprintln(w)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package gqltypes
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+type Loc struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func (l Loc) String() string {
+	return fmt.Sprintf("%v,%v", l.Line, l.Column)
+}
+
+func locOf(n parse.Node) Loc {
+	return Loc{n.Loc().Line + 1, n.Loc().Column + 1}
+}
+
+type Field struct {
+	Name       string   `json:"name"`
+	Type       string   `json:"type,omitempty"`
+	Arguments  []string `json:"arguments,omitempty"`
+	Directives []string `json:"directives,omitempty"`
+	Loc        Loc      `json:"loc"`
+}
+
+// Signature formats the field the way it is written in the schema, without
+// its directives.
+func (f Field) Signature() string {
+	s := f.Name
+	if len(f.Arguments) > 0 {
+		s += "(" + strings.Join(f.Arguments, ", ") + ")"
+	}
+	if f.Type != "" {
+		s += ": " + f.Type
+	}
+	return s
+}
+
+type Type struct {
+	Name       string   `json:"name"`
+	Kind       string   `json:"kind"`
+	Interfaces []string `json:"interfaces,omitempty"`
+	Members    []string `json:"members,omitempty"`
+	Directives []string `json:"directives,omitempty"`
+	Fields     []Field  `json:"fields,omitempty"`
+	Values     []Field  `json:"values,omitempty"`
+	Loc        Loc      `json:"loc"`
+}
+
+// Kinds are the values accepted by the kind filter.
+var Kinds = []string{"object", "interface", "input", "enum", "scalar", "union"}
+
+// Query selects types from a schema. Empty filters match everything.
+type Query struct {
+	Kinds     []string
+	Directive string
+	FieldType string
+	Name      string
+}
+
+// Check reports whether the filters are well formed.
+func (q Query) Check() error {
+	if _, err := path.Match(q.Name, ""); err != nil {
+		return fmt.Errorf("invalid name pattern %q: %v", q.Name, err)
+	}
+	return nil
+}
+
+func directives(nodes []parse.Node) []string {
+	if len(nodes) == 0 {
+		return nil
+	}
+	ds := make([]string, len(nodes))
+	for i, n := range nodes {
+		ds[i] = parse.PrintDirective(n)
+	}
+	return ds
+}
+
+func hasDirective(nodes []parse.Node, name string) bool {
+	for _, n := range nodes {
+		if dn, ok := n.(parse.DirectiveNode); ok && dn.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func typeName(n parse.Node) string {
+	if tn, ok := n.(parse.TypeNode); ok {
+		return tn.Name
+	}
+	return ""
+}
+
+func field(fn parse.FieldNode) Field {
+	f := Field{
+		Name:       fn.Name,
+		Type:       parse.PrintType(fn.Type),
+		Directives: directives(fn.Directives),
+		Loc:        locOf(fn),
+	}
+	for _, n := range fn.Params {
+		pn := n.(parse.ParamNode)
+		arg := fmt.Sprintf("%v: %v", pn.Name, parse.PrintType(pn.Type))
+		if pn.DefaultValue != nil {
+			arg += " = " + parse.PrintValue(pn.DefaultValue)
+		}
+		f.Arguments = append(f.Arguments, arg)
+	}
+	return f
+}
+
+func (q Query) matchesField(fn parse.FieldNode, typeDirective bool) bool {
+	if q.Directive != "" && !typeDirective && !hasDirective(fn.Directives, q.Directive) {
+		return false
+	}
+	if q.FieldType != "" {
+		uses := typeName(fn.Type) == q.FieldType
+		for _, n := range fn.Params {
+			if typeName(n.(parse.ParamNode).Type) == q.FieldType {
+				uses = true
+			}
+		}
+		if !uses {
+			return false
+		}
+	}
+	return true
+}
+
+func (q Query) matchesKind(kind string) bool {
+	if len(q.Kinds) == 0 {
+		return true
+	}
+	for _, k := range q.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// selectType converts a definition into a Type, keeping only the fields
+// that match the field level filters. It reports false when the
+// definition does not match.
+func (q Query) selectType(n parse.Node) (Type, bool) {
+	var t Type
+	var typeDirectives []parse.Node
+	var fields []parse.Node
+	hasFields := false
+
+	switch dn := n.(type) {
+	case parse.TypeDefNode:
+		t = Type{Name: dn.Name, Kind: "object", Interfaces: dn.Interfaces}
+		if dn.Input {
+			t.Kind = "input"
+		} else if dn.Interface {
+			t.Kind = "interface"
+		}
+		typeDirectives, fields, hasFields = dn.Directives, dn.Fields, true
+	case parse.EnumDefNode:
+		t = Type{Name: dn.Name, Kind: "enum"}
+		typeDirectives = dn.Directives
+		for _, n := range dn.Values {
+			evn := n.(parse.EnumValueNode)
+			if q.Directive != "" && !hasDirective(dn.Directives, q.Directive) && !hasDirective(evn.Directives, q.Directive) {
+				continue
+			}
+			t.Values = append(t.Values, Field{Name: evn.Name, Directives: directives(evn.Directives), Loc: locOf(evn)})
+		}
+		if q.FieldType != "" || (q.Directive != "" && len(t.Values) == 0) {
+			return t, false
+		}
+	case parse.ScalarDefNode:
+		t = Type{Name: dn.Name, Kind: "scalar"}
+		typeDirectives = dn.Directives
+		if q.FieldType != "" || (q.Directive != "" && !hasDirective(dn.Directives, q.Directive)) {
+			return t, false
+		}
+	case parse.UnionDefNode:
+		t = Type{Name: dn.Name, Kind: "union", Members: dn.Types}
+		typeDirectives = dn.Directives
+		if q.Directive != "" && !hasDirective(dn.Directives, q.Directive) {
+			return t, false
+		}
+		if q.FieldType != "" {
+			member := false
+			for _, m := range dn.Types {
+				if m == q.FieldType {
+					member = true
+				}
+			}
+			if !member {
+				return t, false
+			}
+		}
+	default:
+		return t, false
+	}
+
+	if !q.matchesKind(t.Kind) {
+		return t, false
+	}
+	if q.Name != "" {
+		if ok, _ := path.Match(q.Name, t.Name); !ok {
+			return t, false
+		}
+	}
+
+	t.Directives = directives(typeDirectives)
+	t.Loc = locOf(n)
+
+	typeDirective := q.Directive != "" && hasDirective(typeDirectives, q.Directive)
+	for _, n := range fields {
+		fn := n.(parse.FieldNode)
+		if q.matchesField(fn, typeDirective) {
+			t.Fields = append(t.Fields, field(fn))
+		}
+	}
+	if hasFields && len(t.Fields) == 0 && (q.FieldType != "" || (q.Directive != "" && !typeDirective)) {
+		return t, false
+	}
+	return t, true
+}
+
+// Run selects the matching types of a parsed schema in document order, or
+// by name when sorted is set.
+func (q Query) Run(doc parse.Node, sorted bool) []Type {
+	types := make([]Type, 0)
+	parse.Traverse(doc, func(n parse.Node) bool {
+		if _, ok := n.(parse.DocumentNode); ok {
+			return true
+		}
+		if t, ok := q.selectType(n); ok {
+			types = append(types, t)
+		}
+		return false
+	})
+
+	if sorted {
+		sort.SliceStable(types, func(i, j int) bool {
+			return types[i].Name < types[j].Name
+		})
+	}
+	return types
+}
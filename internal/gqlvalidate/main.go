@@ -0,0 +1,34 @@
+package gqlvalidate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+	"github.com/beauknowssoftware/go-gql-gen/pkg/validate"
+)
+
+func Run() {
+	schemaBytes, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read schema from stdin: %v\n", err)
+		os.Exit(1)
+	}
+	schema := string(schemaBytes)
+
+	p := parse.New(parse.NewLexer(schema))
+	rnode, perr := p.Parse()
+	if perr != nil {
+		fmt.Fprintf(os.Stderr, "failed to parse schema: %v (%v)\n", perr.Error, perr.Token)
+		os.Exit(1)
+	}
+
+	errs := validate.Validate(rnode)
+	for _, err := range errs {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if len(errs) > 0 {
+		os.Exit(1)
+	}
+}
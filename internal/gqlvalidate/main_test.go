@@ -0,0 +1,68 @@
+package gqlvalidate_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func OpenFile(t *testing.T, filename string) *os.File {
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatalf("failed to open file %v", filename)
+	}
+	return f
+}
+
+func ReadFile(t *testing.T, filename string) string {
+	d, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read file %v", filename)
+	}
+	return string(d)
+}
+
+func Test_Main(t *testing.T) {
+	tests := map[string]struct {
+		expectedExitCode int
+	}{
+		"valid":   {},
+		"invalid": {expectedExitCode: 1},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			gqls := OpenFile(t, "testdata/"+name+".graphqls")
+
+			cmd := exec.Command("gql-validate")
+			var outBuff, errBuff bytes.Buffer
+			cmd.Stdin = gqls
+			cmd.Stdout = &outBuff
+			cmd.Stderr = &errBuff
+
+			exitCode := 0
+			if err := cmd.Run(); err != nil {
+				exitErr, ok := err.(*exec.ExitError)
+				if !ok {
+					t.Fatalf("failed to run %v\n%v\n%v", err, outBuff.String(), errBuff.String())
+				}
+				exitCode = exitErr.ExitCode()
+			}
+			if exitCode != test.expectedExitCode {
+				t.Fatalf("expected exit code %v got %v\n%v", test.expectedExitCode, exitCode, errBuff.String())
+			}
+
+			expected := ""
+			if test.expectedExitCode != 0 {
+				expected = ReadFile(t, "testdata/"+name+".txt")
+			}
+			if diff := cmp.Diff(expected, errBuff.String()); diff != "" {
+				t.Fatalf("mismatch (-expected,+got) %v", diff)
+			}
+		})
+	}
+}
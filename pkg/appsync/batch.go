@@ -0,0 +1,88 @@
+package appsync
+
+import "context"
+
+// Dispatch answers the events of an invocation, which AppSync sends as an
+// array when it batches a field. Events of the same field are handed to
+// batch together, with ctx carrying the first of them. Fields that batch
+// does not know are resolved one event at a time by single. The responses
+// are in the order of the events.
+func Dispatch(ctx context.Context, events []Event,
+	batch func(ctx context.Context, events []Event) ([]Response, bool),
+	single func(ctx context.Context, event Event) (interface{}, error)) []Response {
+	responses := make([]Response, len(events))
+	var fields []string
+	groups := make(map[string][]int)
+	for i, e := range events {
+		field := e.Info.ParentTypeName + "." + e.Info.FieldName
+		if _, ok := groups[field]; !ok {
+			fields = append(fields, field)
+		}
+		groups[field] = append(groups[field], i)
+	}
+
+	for _, field := range fields {
+		items := groups[field]
+		group := Pick(events, items)
+		if rs, ok := batch(WithEvent(ctx, group[0]), group); ok {
+			for j, i := range items {
+				responses[i] = rs[j]
+			}
+			continue
+		}
+		for _, i := range items {
+			responses[i] = Respond(single(WithEvent(ctx, events[i]), events[i]))
+		}
+	}
+	return responses
+}
+
+// Batch answers the events of a batched field. decode reads event i, and
+// events it fails on are answered with its error. resolve is called once
+// with the positions of the remaining events and returns their results,
+// along with no errors, an error for each result or one error for all.
+func Batch[T any](events []Event, decode func(i int, e Event) error, resolve func(items []int) ([]T, []error)) []Response {
+	responses := make([]Response, len(events))
+	var items []int
+	for i, e := range events {
+		if err := decode(i, e); err != nil {
+			responses[i] = Respond(nil, err)
+			continue
+		}
+		items = append(items, i)
+	}
+	if len(items) == 0 {
+		return responses
+	}
+
+	results, errs := resolve(items)
+	for j, i := range items {
+		var err error
+		switch {
+		case len(errs) == len(items):
+			err = errs[j]
+		case len(errs) == 1:
+			err = errs[0]
+		case len(errs) != 0:
+			err = Errorf(ErrorTypeInternal, "batch resolver returned %v errors for %v requests", len(errs), len(items))
+		}
+		if err == nil && len(results) != len(items) {
+			err = Errorf(ErrorTypeInternal, "batch resolver returned %v results for %v requests", len(results), len(items))
+		}
+		if err != nil {
+			responses[i] = Respond(nil, err)
+			continue
+		}
+		responses[i] = Respond(results[j], nil)
+	}
+	return responses
+}
+
+// Pick returns the elements of s at the positions items.
+func Pick[T any](s []T, items []int) []T {
+	picked := make([]T, len(items))
+	for j, i := range items {
+		picked[j] = s[i]
+	}
+	return picked
+}
@@ -0,0 +1,76 @@
+package appsync_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/appsync"
+)
+
+func TestBatch(t *testing.T) {
+	events := make([]appsync.Event, 3)
+	decodeSecond := func(i int, e appsync.Event) error {
+		if i == 1 {
+			return appsync.Errorf(appsync.ErrorTypeBadRequest, "bad")
+		}
+		return nil
+	}
+
+	tests := map[string]struct {
+		results  []int
+		errs     []error
+		expected []appsync.Response
+	}{
+		"results": {
+			results: []int{1, 3},
+			expected: []appsync.Response{
+				{Data: 1},
+				{ErrorType: appsync.ErrorTypeBadRequest, ErrorMessage: "bad"},
+				{Data: 3},
+			},
+		},
+		"item errors": {
+			results: []int{1, 0},
+			errs:    []error{nil, errors.New("failed")},
+			expected: []appsync.Response{
+				{Data: 1},
+				{ErrorType: appsync.ErrorTypeBadRequest, ErrorMessage: "bad"},
+				{ErrorType: appsync.ErrorTypeInternal, ErrorMessage: "failed"},
+			},
+		},
+		"batch error": {
+			errs: []error{errors.New("down")},
+			expected: []appsync.Response{
+				{ErrorType: appsync.ErrorTypeInternal, ErrorMessage: "down"},
+				{ErrorType: appsync.ErrorTypeBadRequest, ErrorMessage: "bad"},
+				{ErrorType: appsync.ErrorTypeInternal, ErrorMessage: "down"},
+			},
+		},
+		"missing results": {
+			results: []int{1},
+			expected: []appsync.Response{
+				{ErrorType: appsync.ErrorTypeInternal, ErrorMessage: "batch resolver returned 1 results for 2 requests"},
+				{ErrorType: appsync.ErrorTypeBadRequest, ErrorMessage: "bad"},
+				{ErrorType: appsync.ErrorTypeInternal, ErrorMessage: "batch resolver returned 1 results for 2 requests"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var items []int
+			got := appsync.Batch(events, decodeSecond, func(is []int) ([]int, []error) {
+				items = is
+				return test.results, test.errs
+			})
+			if diff := cmp.Diff([]int{0, 2}, items); diff != "" {
+				t.Fatalf("items mismatch (-expected,+got) %v", diff)
+			}
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Fatalf("mismatch (-expected,+got) %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Package appsync decodes the events AppSync sends to direct Lambda
+// resolvers and encodes their responses.
+package appsync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Event is the payload of a direct Lambda resolver invocation.
+type Event struct {
+	Arguments json.RawMessage `json:"arguments"`
+	Source    json.RawMessage `json:"source"`
+	// Identity is nil for API key authorization.
+	Identity *Identity              `json:"identity"`
+	Request  Request                `json:"request"`
+	Prev     *Prev                  `json:"prev"`
+	Info     Info                   `json:"info"`
+	Stash    map[string]interface{} `json:"stash"`
+}
+
+// Identity describes the caller. Which fields are set depends on the
+// authorization mode.
+type Identity struct {
+	// Cognito user pools and OIDC.
+	Sub                 string                 `json:"sub"`
+	Issuer              string                 `json:"issuer"`
+	Username            string                 `json:"username"`
+	Claims              map[string]interface{} `json:"claims"`
+	SourceIP            []string               `json:"sourceIp"`
+	DefaultAuthStrategy string                 `json:"defaultAuthStrategy"`
+	Groups              []string               `json:"groups"`
+	// IAM.
+	AccountID                   string `json:"accountId"`
+	CognitoIdentityPoolID       string `json:"cognitoIdentityPoolId"`
+	CognitoIdentityID           string `json:"cognitoIdentityId"`
+	CognitoIdentityAuthType     string `json:"cognitoIdentityAuthType"`
+	CognitoIdentityAuthProvider string `json:"cognitoIdentityAuthProvider"`
+	UserArn                     string `json:"userArn"`
+	// Lambda authorizers.
+	ResolverContext map[string]interface{} `json:"resolverContext"`
+}
+
+// Request holds the HTTP request of the GraphQL operation.
+type Request struct {
+	Headers map[string]string `json:"headers"`
+}
+
+// Prev holds the result of the previous function of a pipeline resolver.
+type Prev struct {
+	Result json.RawMessage `json:"result"`
+}
+
+// Info describes the field being resolved.
+type Info struct {
+	FieldName           string                 `json:"fieldName"`
+	ParentTypeName      string                 `json:"parentTypeName"`
+	Variables           map[string]interface{} `json:"variables"`
+	SelectionSetList    []string               `json:"selectionSetList"`
+	SelectionSetGraphQL string                 `json:"selectionSetGraphQL"`
+}
+
+func decode(what string, d json.RawMessage, v interface{}) error {
+	if len(d) == 0 || bytes.Equal(bytes.TrimSpace(d), []byte("null")) {
+		return nil
+	}
+	if err := json.Unmarshal(d, v); err != nil {
+		return &Error{Type: ErrorTypeBadRequest, Message: fmt.Sprintf("invalid %v: %v", what, err)}
+	}
+	return nil
+}
+
+// DecodeArguments decodes the arguments of the field into v.
+func (e Event) DecodeArguments(v interface{}) error {
+	return decode("arguments", e.Arguments, v)
+}
+
+// DecodeSource decodes the parent object of the field into v. It leaves v
+// alone for root fields, which have no source.
+func (e Event) DecodeSource(v interface{}) error {
+	return decode("source", e.Source, v)
+}
+
+type eventKey struct{}
+
+// WithEvent returns a context carrying the event, which lets resolvers
+// reach the identity and selection set.
+func WithEvent(ctx context.Context, e Event) context.Context {
+	return context.WithValue(ctx, eventKey{}, e)
+}
+
+// EventFrom returns the event carried by ctx.
+func EventFrom(ctx context.Context) (Event, bool) {
+	e, ok := ctx.Value(eventKey{}).(Event)
+	return e, ok
+}
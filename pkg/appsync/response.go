@@ -0,0 +1,64 @@
+package appsync
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/gql"
+)
+
+// Error types set by this package. Resolvers may use any other type.
+const (
+	ErrorTypeBadRequest   = "BadRequest"
+	ErrorTypeValidation   = "ValidationError"
+	ErrorTypeUnknownField = "UnknownField"
+	ErrorTypeInternal     = "InternalError"
+)
+
+// Error is an error with an AppSync error type.
+type Error struct {
+	Type    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Errorf returns an Error of the given type.
+func Errorf(errorType, format string, args ...interface{}) error {
+	return &Error{Type: errorType, Message: fmt.Sprintf(format, args...)}
+}
+
+// UnknownField is returned for fields without a resolver.
+func UnknownField(info Info) error {
+	return Errorf(ErrorTypeUnknownField, "no resolver for %v.%v", info.ParentTypeName, info.FieldName)
+}
+
+// Response is the result of a resolver in the shape that a response
+// mapping template turns into data or an error with
+// $util.error($ctx.result.errorMessage, $ctx.result.errorType).
+type Response struct {
+	Data         interface{} `json:"data"`
+	ErrorType    string      `json:"errorType,omitempty"`
+	ErrorMessage string      `json:"errorMessage,omitempty"`
+}
+
+// Respond maps the result of a resolver to a Response. An Error keeps its
+// type, validation errors from the generated Validate methods are
+// ValidationError and other errors are InternalError.
+func Respond(data interface{}, err error) Response {
+	if err == nil {
+		return Response{Data: data}
+	}
+	var aerr *Error
+	var ferr *gql.FieldError
+	switch {
+	case errors.As(err, &aerr):
+		return Response{ErrorType: aerr.Type, ErrorMessage: err.Error()}
+	case errors.As(err, &ferr):
+		return Response{ErrorType: ErrorTypeValidation, ErrorMessage: err.Error()}
+	default:
+		return Response{ErrorType: ErrorTypeInternal, ErrorMessage: err.Error()}
+	}
+}
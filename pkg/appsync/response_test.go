@@ -0,0 +1,52 @@
+package appsync_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/appsync"
+	"github.com/beauknowssoftware/go-gql-gen/pkg/gql"
+)
+
+func TestRespond(t *testing.T) {
+	var invalid gql.Errors
+	invalid.Missing("input.tags")
+
+	tests := map[string]struct {
+		data     interface{}
+		err      error
+		expected appsync.Response
+	}{
+		"data": {
+			data:     []string{"a"},
+			expected: appsync.Response{Data: []string{"a"}},
+		},
+		"typed": {
+			err:      fmt.Errorf("wrapped: %w", appsync.Errorf("NotFound", "no account %v", 1)),
+			expected: appsync.Response{ErrorType: "NotFound", ErrorMessage: "wrapped: no account 1"},
+		},
+		"validation": {
+			err:      invalid.Err(),
+			expected: appsync.Response{ErrorType: appsync.ErrorTypeValidation, ErrorMessage: "input.tags: required value is missing"},
+		},
+		"unknown": {
+			err:      appsync.UnknownField(appsync.Info{ParentTypeName: "Query", FieldName: "nope"}),
+			expected: appsync.Response{ErrorType: appsync.ErrorTypeUnknownField, ErrorMessage: "no resolver for Query.nope"},
+		},
+		"other": {
+			err:      errors.New("boom"),
+			expected: appsync.Response{ErrorType: appsync.ErrorTypeInternal, ErrorMessage: "boom"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(test.expected, appsync.Respond(test.data, test.err)); diff != "" {
+				t.Fatalf("mismatch (-expected,+got) %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,667 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+type Criticality int
+
+const (
+	Safe Criticality = iota
+	Dangerous
+	Breaking
+)
+
+func (c Criticality) String() string {
+	switch c {
+	case Safe:
+		return "safe"
+	case Dangerous:
+		return "dangerous"
+	case Breaking:
+		return "breaking"
+	default:
+		return "unknown"
+	}
+}
+
+func ParseCriticality(s string) (Criticality, error) {
+	for _, c := range []Criticality{Safe, Dangerous, Breaking} {
+		if c.String() == s {
+			return c, nil
+		}
+	}
+	return Safe, fmt.Errorf("unknown criticality %v", s)
+}
+
+func (c Criticality) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.String())
+}
+
+type ChangeType string
+
+const (
+	TypeAdded                   ChangeType = "TYPE_ADDED"
+	TypeRemoved                 ChangeType = "TYPE_REMOVED"
+	TypeKindChanged             ChangeType = "TYPE_KIND_CHANGED"
+	TypeDescriptionChanged      ChangeType = "TYPE_DESCRIPTION_CHANGED"
+	FieldAdded                  ChangeType = "FIELD_ADDED"
+	FieldRemoved                ChangeType = "FIELD_REMOVED"
+	FieldTypeChanged            ChangeType = "FIELD_TYPE_CHANGED"
+	FieldDescriptionChanged     ChangeType = "FIELD_DESCRIPTION_CHANGED"
+	FieldDeprecated             ChangeType = "FIELD_DEPRECATED"
+	FieldUndeprecated           ChangeType = "FIELD_UNDEPRECATED"
+	FieldDefaultValueChanged    ChangeType = "FIELD_DEFAULT_VALUE_CHANGED"
+	ArgAdded                    ChangeType = "ARG_ADDED"
+	ArgRemoved                  ChangeType = "ARG_REMOVED"
+	ArgTypeChanged              ChangeType = "ARG_TYPE_CHANGED"
+	ArgDefaultValueChanged      ChangeType = "ARG_DEFAULT_VALUE_CHANGED"
+	EnumValueAdded              ChangeType = "ENUM_VALUE_ADDED"
+	EnumValueRemoved            ChangeType = "ENUM_VALUE_REMOVED"
+	UnionMemberAdded            ChangeType = "UNION_MEMBER_ADDED"
+	UnionMemberRemoved          ChangeType = "UNION_MEMBER_REMOVED"
+	InterfaceAdded              ChangeType = "INTERFACE_ADDED"
+	InterfaceRemoved            ChangeType = "INTERFACE_REMOVED"
+	DirectiveAdded              ChangeType = "DIRECTIVE_ADDED"
+	DirectiveRemoved            ChangeType = "DIRECTIVE_REMOVED"
+	DirectiveLocationAdded      ChangeType = "DIRECTIVE_LOCATION_ADDED"
+	DirectiveLocationRemoved    ChangeType = "DIRECTIVE_LOCATION_REMOVED"
+	DirectiveUsageAdded         ChangeType = "DIRECTIVE_USAGE_ADDED"
+	DirectiveUsageRemoved       ChangeType = "DIRECTIVE_USAGE_REMOVED"
+	DirectiveUsageChanged       ChangeType = "DIRECTIVE_USAGE_CHANGED"
+	SchemaOperationTypeChanged  ChangeType = "SCHEMA_OPERATION_TYPE_CHANGED"
+	SchemaOperationTypeAdded    ChangeType = "SCHEMA_OPERATION_TYPE_ADDED"
+	SchemaOperationTypeRemoved  ChangeType = "SCHEMA_OPERATION_TYPE_REMOVED"
+	DirectiveRepeatableRemoved  ChangeType = "DIRECTIVE_REPEATABLE_REMOVED"
+	DirectiveRepeatableAdded    ChangeType = "DIRECTIVE_REPEATABLE_ADDED"
+	EnumValueDeprecated         ChangeType = "ENUM_VALUE_DEPRECATED"
+	EnumValueUndeprecated       ChangeType = "ENUM_VALUE_UNDEPRECATED"
+	EnumValueDescriptionChanged ChangeType = "ENUM_VALUE_DESCRIPTION_CHANGED"
+	ArgDescriptionChanged       ChangeType = "ARG_DESCRIPTION_CHANGED"
+	DirectiveDescriptionChanged ChangeType = "DIRECTIVE_DESCRIPTION_CHANGED"
+	SchemaDescriptionChanged    ChangeType = "SCHEMA_DESCRIPTION_CHANGED"
+)
+
+// Side tells which of the two schemas a location points into.
+type Side string
+
+const (
+	Old Side = "old"
+	New Side = "new"
+)
+
+type Change struct {
+	Criticality Criticality
+	Type        ChangeType
+	Path        string
+	Message     string
+	// Loc points into the schema given by Side: the old one for removed
+	// elements and the new one for everything else.
+	Side Side
+	Loc  parse.Loc
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%v %v %v: %v", c.Side, c.Loc, c.Criticality, c.Message)
+}
+
+func (c Change) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Criticality Criticality `json:"criticality"`
+		Type        ChangeType  `json:"type"`
+		Path        string      `json:"path"`
+		Message     string      `json:"message"`
+		Side        Side        `json:"side"`
+		Line        int         `json:"line"`
+		Column      int         `json:"column"`
+	}{c.Criticality, c.Type, c.Path, c.Message, c.Side, c.Loc.Line + 1, c.Loc.Column + 1})
+}
+
+type differ struct {
+	changes []Change
+}
+
+// add records a change located at a node of the new schema.
+func (d *differ) add(c Criticality, t ChangeType, loc parse.Node, path string, format string, args ...interface{}) {
+	d.record(New, c, t, loc, path, fmt.Sprintf(format, args...))
+}
+
+// removed records a change located at a node of the old schema, which is
+// the only place a removed element can be found.
+func (d *differ) removed(c Criticality, t ChangeType, loc parse.Node, path string, format string, args ...interface{}) {
+	d.record(Old, c, t, loc, path, fmt.Sprintf(format, args...))
+}
+
+func (d *differ) record(side Side, c Criticality, t ChangeType, loc parse.Node, path string, message string) {
+	d.changes = append(d.changes, Change{
+		Criticality: c,
+		Type:        t,
+		Path:        path,
+		Message:     message,
+		Side:        side,
+		Loc:         loc.Loc(),
+	})
+}
+
+type namedNode struct {
+	name string
+	node parse.Node
+}
+
+func kindOf(n parse.Node) string {
+	switch dn := n.(type) {
+	case parse.TypeDefNode:
+		switch {
+		case dn.Input:
+			return "input object"
+		case dn.Interface:
+			return "interface"
+		default:
+			return "object"
+		}
+	case parse.EnumDefNode:
+		return "enum"
+	case parse.ScalarDefNode:
+		return "scalar"
+	case parse.UnionDefNode:
+		return "union"
+	}
+	return ""
+}
+
+func definitions(n parse.Node) (types []namedNode, directives []namedNode, schema *parse.SchemaNode) {
+	for _, d := range n.(parse.DocumentNode).Definitions {
+		switch dn := d.(type) {
+		case parse.TypeDefNode:
+			types = append(types, namedNode{dn.Name, dn})
+		case parse.EnumDefNode:
+			types = append(types, namedNode{dn.Name, dn})
+		case parse.ScalarDefNode:
+			types = append(types, namedNode{dn.Name, dn})
+		case parse.UnionDefNode:
+			types = append(types, namedNode{dn.Name, dn})
+		case parse.DirectiveDefNode:
+			directives = append(directives, namedNode{dn.Name, dn})
+		case parse.SchemaNode:
+			sn := dn
+			schema = &sn
+		}
+	}
+	return
+}
+
+func find(nodes []namedNode, name string) (parse.Node, bool) {
+	for _, n := range nodes {
+		if n.name == name {
+			return n.node, true
+		}
+	}
+	return nil, false
+}
+
+func description(n parse.Node) string {
+	switch dn := n.(type) {
+	case parse.TypeDefNode:
+		return dn.Description
+	case parse.EnumDefNode:
+		return dn.Description
+	case parse.ScalarDefNode:
+		return dn.Description
+	case parse.UnionDefNode:
+		return dn.Description
+	}
+	return ""
+}
+
+func directivesOf(n parse.Node) []parse.Node {
+	switch dn := n.(type) {
+	case parse.TypeDefNode:
+		return dn.Directives
+	case parse.EnumDefNode:
+		return dn.Directives
+	case parse.ScalarDefNode:
+		return dn.Directives
+	case parse.UnionDefNode:
+		return dn.Directives
+	}
+	return nil
+}
+
+func typeString(n parse.Node) string {
+	tn := n.(parse.TypeNode)
+	s := tn.Name
+	if tn.Multiple {
+		if tn.NonNullElements {
+			s += "!"
+		}
+		s = "[" + s + "]"
+	}
+	if tn.Required {
+		s += "!"
+	}
+	return s
+}
+
+// isSafeOutputChange reports whether values of the new type can still be
+// read by clients expecting the old type: nullability may only be tightened.
+func isSafeOutputChange(oldType, newType parse.Node) bool {
+	o, n := oldType.(parse.TypeNode), newType.(parse.TypeNode)
+	if o.Name != n.Name || o.Multiple != n.Multiple {
+		return false
+	}
+	if o.Required && !n.Required {
+		return false
+	}
+	return !(o.NonNullElements && !n.NonNullElements)
+}
+
+// isSafeInputChange reports whether values clients send for the old type
+// are still accepted by the new type: nullability may only be relaxed.
+func isSafeInputChange(oldType, newType parse.Node) bool {
+	o, n := oldType.(parse.TypeNode), newType.(parse.TypeNode)
+	if o.Name != n.Name || o.Multiple != n.Multiple {
+		return false
+	}
+	if n.Required && !o.Required {
+		return false
+	}
+	return !(n.NonNullElements && !o.NonNullElements)
+}
+
+func printValue(n parse.Node) string {
+	if n == nil {
+		return "none"
+	}
+	return parse.PrintValue(n)
+}
+
+func deprecation(directives []parse.Node) (bool, string) {
+	for _, n := range directives {
+		dn := n.(parse.DirectiveNode)
+		if dn.Name != "deprecated" {
+			continue
+		}
+		for _, a := range dn.Arguments {
+			if an := a.(parse.ArgumentNode); an.Name == "reason" {
+				return true, parse.PrintValue(an.Value)
+			}
+		}
+		return true, ""
+	}
+	return false, ""
+}
+
+func directiveString(dn parse.DirectiveNode) string {
+	if len(dn.Arguments) == 0 {
+		return "@" + dn.Name
+	}
+	args := make([]string, len(dn.Arguments))
+	for i, a := range dn.Arguments {
+		an := a.(parse.ArgumentNode)
+		args[i] = an.Name + ": " + parse.PrintValue(an.Value)
+	}
+	return fmt.Sprintf("@%v(%v)", dn.Name, strings.Join(args, ", "))
+}
+
+func (d *differ) directiveUsages(path string, oldDirectives, newDirectives []parse.Node) {
+	index := func(nodes []parse.Node) map[string]parse.DirectiveNode {
+		m := make(map[string]parse.DirectiveNode)
+		for _, n := range nodes {
+			dn := n.(parse.DirectiveNode)
+			if dn.Name != "deprecated" {
+				m[dn.Name] = dn
+			}
+		}
+		return m
+	}
+	olds, news := index(oldDirectives), index(newDirectives)
+
+	for _, n := range oldDirectives {
+		dn := n.(parse.DirectiveNode)
+		if dn.Name == "deprecated" {
+			continue
+		}
+		nn, ok := news[dn.Name]
+		if !ok {
+			d.removed(Dangerous, DirectiveUsageRemoved, dn, path, "directive @%v was removed from %v", dn.Name, path)
+		} else if directiveString(dn) != directiveString(nn) {
+			d.add(Dangerous, DirectiveUsageChanged, nn, path, "directive on %v changed from %v to %v", path, directiveString(dn), directiveString(nn))
+		}
+	}
+	for _, n := range newDirectives {
+		dn := n.(parse.DirectiveNode)
+		if _, ok := olds[dn.Name]; !ok && dn.Name != "deprecated" {
+			d.add(Dangerous, DirectiveUsageAdded, dn, path, "directive @%v was added to %v", dn.Name, path)
+		}
+	}
+}
+
+func (d *differ) args(path string, oldParams, newParams []parse.Node, directive bool) {
+	for _, o := range oldParams {
+		op := o.(parse.ParamNode)
+		apath := fmt.Sprintf("%v(%v)", path, op.Name)
+
+		var np *parse.ParamNode
+		for _, n := range newParams {
+			if pn := n.(parse.ParamNode); pn.Name == op.Name {
+				np = &pn
+			}
+		}
+		if np == nil {
+			d.removed(Breaking, ArgRemoved, op, apath, "argument %v was removed", apath)
+			continue
+		}
+
+		if !isSafeInputChange(op.Type, np.Type) {
+			d.add(Breaking, ArgTypeChanged, np.Type, apath, "argument %v changed type from %v to %v", apath, typeString(op.Type), typeString(np.Type))
+		} else if typeString(op.Type) != typeString(np.Type) {
+			d.add(Safe, ArgTypeChanged, np.Type, apath, "argument %v changed type from %v to %v", apath, typeString(op.Type), typeString(np.Type))
+		}
+		if printValue(op.DefaultValue) != printValue(np.DefaultValue) {
+			d.add(Dangerous, ArgDefaultValueChanged, *np, apath, "argument %v default value changed from %v to %v", apath, printValue(op.DefaultValue), printValue(np.DefaultValue))
+		}
+		if op.Description != np.Description {
+			d.add(Safe, ArgDescriptionChanged, *np, apath, "argument %v description changed", apath)
+		}
+	}
+
+	for _, n := range newParams {
+		np := n.(parse.ParamNode)
+		apath := fmt.Sprintf("%v(%v)", path, np.Name)
+
+		found := false
+		for _, o := range oldParams {
+			if o.(parse.ParamNode).Name == np.Name {
+				found = true
+			}
+		}
+		if found {
+			continue
+		}
+		switch {
+		case np.Type.(parse.TypeNode).Required && np.DefaultValue == nil:
+			d.add(Breaking, ArgAdded, np, apath, "required argument %v was added", apath)
+		case directive:
+			d.add(Safe, ArgAdded, np, apath, "optional argument %v was added", apath)
+		default:
+			d.add(Dangerous, ArgAdded, np, apath, "optional argument %v was added", apath)
+		}
+	}
+}
+
+func (d *differ) fields(name string, input bool, oldFields, newFields []parse.Node) {
+	for _, o := range oldFields {
+		of := o.(parse.FieldNode)
+		path := name + "." + of.Name
+
+		var nf *parse.FieldNode
+		for _, n := range newFields {
+			if fn := n.(parse.FieldNode); fn.Name == of.Name {
+				nf = &fn
+			}
+		}
+		if nf == nil {
+			d.removed(Breaking, FieldRemoved, of, path, "field %v was removed", path)
+			continue
+		}
+
+		safe := isSafeOutputChange(of.Type, nf.Type)
+		if input {
+			safe = isSafeInputChange(of.Type, nf.Type)
+		}
+		if !safe {
+			d.add(Breaking, FieldTypeChanged, nf.Type, path, "field %v changed type from %v to %v", path, typeString(of.Type), typeString(nf.Type))
+		} else if typeString(of.Type) != typeString(nf.Type) {
+			d.add(Safe, FieldTypeChanged, nf.Type, path, "field %v changed type from %v to %v", path, typeString(of.Type), typeString(nf.Type))
+		}
+
+		if printValue(of.DefaultValue) != printValue(nf.DefaultValue) {
+			d.add(Dangerous, FieldDefaultValueChanged, *nf, path, "field %v default value changed from %v to %v", path, printValue(of.DefaultValue), printValue(nf.DefaultValue))
+		}
+		if of.Description != nf.Description {
+			d.add(Safe, FieldDescriptionChanged, *nf, path, "field %v description changed", path)
+		}
+
+		od, _ := deprecation(of.Directives)
+		nd, reason := deprecation(nf.Directives)
+		if !od && nd {
+			if reason != "" {
+				d.add(Safe, FieldDeprecated, *nf, path, "field %v was deprecated with reason %v", path, reason)
+			} else {
+				d.add(Safe, FieldDeprecated, *nf, path, "field %v was deprecated", path)
+			}
+		} else if od && !nd {
+			d.add(Safe, FieldUndeprecated, *nf, path, "field %v is no longer deprecated", path)
+		}
+
+		d.args(path, of.Params, nf.Params, false)
+		d.directiveUsages(path, of.Directives, nf.Directives)
+	}
+
+	for _, n := range newFields {
+		nf := n.(parse.FieldNode)
+		path := name + "." + nf.Name
+
+		found := false
+		for _, o := range oldFields {
+			if o.(parse.FieldNode).Name == nf.Name {
+				found = true
+			}
+		}
+		if found {
+			continue
+		}
+		switch {
+		case input && nf.Type.(parse.TypeNode).Required && nf.DefaultValue == nil:
+			d.add(Breaking, FieldAdded, nf, path, "required input field %v was added", path)
+		case input:
+			d.add(Dangerous, FieldAdded, nf, path, "optional input field %v was added", path)
+		default:
+			d.add(Safe, FieldAdded, nf, path, "field %v was added", path)
+		}
+	}
+}
+
+func diffNames(oldNames, newNames []string) (removed []string, added []string) {
+	has := func(names []string, name string) bool {
+		for _, n := range names {
+			if n == name {
+				return true
+			}
+		}
+		return false
+	}
+	for _, n := range oldNames {
+		if !has(newNames, n) {
+			removed = append(removed, n)
+		}
+	}
+	for _, n := range newNames {
+		if !has(oldNames, n) {
+			added = append(added, n)
+		}
+	}
+	return
+}
+
+func (d *differ) types(name string, o, n parse.Node) {
+	if kindOf(o) != kindOf(n) {
+		d.add(Breaking, TypeKindChanged, n, name, "type %v changed from %v to %v", name, kindOf(o), kindOf(n))
+		return
+	}
+	if description(o) != description(n) {
+		d.add(Safe, TypeDescriptionChanged, n, name, "type %v description changed", name)
+	}
+	d.directiveUsages(name, directivesOf(o), directivesOf(n))
+
+	switch on := o.(type) {
+	case parse.TypeDefNode:
+		nn := n.(parse.TypeDefNode)
+		removed, added := diffNames(on.Interfaces, nn.Interfaces)
+		for _, i := range removed {
+			d.add(Breaking, InterfaceRemoved, nn, name, "%v no longer implements interface %v", name, i)
+		}
+		for _, i := range added {
+			d.add(Dangerous, InterfaceAdded, nn, name, "%v now implements interface %v", name, i)
+		}
+		d.fields(name, on.Input, on.Fields, nn.Fields)
+	case parse.EnumDefNode:
+		nn := n.(parse.EnumDefNode)
+		value := func(nodes []parse.Node, name string) *parse.EnumValueNode {
+			for _, n := range nodes {
+				if evn := n.(parse.EnumValueNode); evn.Name == name {
+					return &evn
+				}
+			}
+			return nil
+		}
+		for _, v := range on.Values {
+			ov := v.(parse.EnumValueNode)
+			path := name + "." + ov.Name
+			nv := value(nn.Values, ov.Name)
+			if nv == nil {
+				d.removed(Breaking, EnumValueRemoved, ov, path, "enum value %v was removed", path)
+				continue
+			}
+			if ov.Description != nv.Description {
+				d.add(Safe, EnumValueDescriptionChanged, *nv, path, "enum value %v description changed", path)
+			}
+			od, _ := deprecation(ov.Directives)
+			nd, _ := deprecation(nv.Directives)
+			if !od && nd {
+				d.add(Safe, EnumValueDeprecated, *nv, path, "enum value %v was deprecated", path)
+			} else if od && !nd {
+				d.add(Safe, EnumValueUndeprecated, *nv, path, "enum value %v is no longer deprecated", path)
+			}
+		}
+		for _, v := range nn.Values {
+			nv := v.(parse.EnumValueNode)
+			if value(on.Values, nv.Name) == nil {
+				path := name + "." + nv.Name
+				d.add(Dangerous, EnumValueAdded, nv, path, "enum value %v was added", path)
+			}
+		}
+	case parse.UnionDefNode:
+		nn := n.(parse.UnionDefNode)
+		removed, added := diffNames(on.Types, nn.Types)
+		for _, m := range removed {
+			d.add(Breaking, UnionMemberRemoved, nn, name, "%v was removed from union %v", m, name)
+		}
+		for _, m := range added {
+			d.add(Dangerous, UnionMemberAdded, nn, name, "%v was added to union %v", m, name)
+		}
+	}
+}
+
+func (d *differ) directives(name string, o, n parse.DirectiveDefNode) {
+	path := "@" + name
+	if o.Description != n.Description {
+		d.add(Safe, DirectiveDescriptionChanged, n, path, "directive %v description changed", path)
+	}
+	if o.Repeatable && !n.Repeatable {
+		d.add(Breaking, DirectiveRepeatableRemoved, n, path, "directive %v is no longer repeatable", path)
+	} else if !o.Repeatable && n.Repeatable {
+		d.add(Safe, DirectiveRepeatableAdded, n, path, "directive %v is now repeatable", path)
+	}
+	removed, added := diffNames(o.Targets, n.Targets)
+	for _, l := range removed {
+		d.add(Breaking, DirectiveLocationRemoved, n, path, "location %v was removed from directive %v", l, path)
+	}
+	for _, l := range added {
+		d.add(Safe, DirectiveLocationAdded, n, path, "location %v was added to directive %v", l, path)
+	}
+	d.args(path, o.Params, n.Params, true)
+}
+
+func operations(sn *parse.SchemaNode, types []namedNode) map[string]string {
+	ops := make(map[string]string)
+	if sn == nil {
+		for _, op := range []string{"Query", "Mutation", "Subscription"} {
+			if _, ok := find(types, op); ok {
+				ops[strings.ToLower(op)] = op
+			}
+		}
+		return ops
+	}
+	for _, n := range sn.Fields {
+		fn := n.(parse.FieldNode)
+		ops[fn.Name] = fn.Type.(parse.TypeNode).Name
+	}
+	return ops
+}
+
+func (d *differ) schema(oldSchema, newSchema *parse.SchemaNode, oldTypes, newTypes []namedNode, newDoc parse.Node) {
+	var loc parse.Node = newDoc
+	if newSchema != nil {
+		loc = *newSchema
+	}
+	if oldSchema != nil && newSchema != nil && oldSchema.Description != newSchema.Description {
+		d.add(Safe, SchemaDescriptionChanged, loc, "schema", "schema description changed")
+	}
+
+	olds, news := operations(oldSchema, oldTypes), operations(newSchema, newTypes)
+	for _, op := range []string{"query", "mutation", "subscription"} {
+		o, hasOld := olds[op]
+		n, hasNew := news[op]
+		switch {
+		case hasOld && !hasNew:
+			d.add(Breaking, SchemaOperationTypeRemoved, loc, "schema."+op, "%v operation type %v was removed", op, o)
+		case !hasOld && hasNew:
+			d.add(Safe, SchemaOperationTypeAdded, loc, "schema."+op, "%v operation type %v was added", op, n)
+		case o != n:
+			d.add(Breaking, SchemaOperationTypeChanged, loc, "schema."+op, "%v operation type changed from %v to %v", op, o, n)
+		}
+	}
+}
+
+// Diff lists the changes between two parsed schemas, classifying each as
+// breaking, dangerous or safe for existing clients. Breaking changes come
+// first.
+func Diff(oldDoc, newDoc parse.Node) []Change {
+	// Without changes the result is empty rather than nil, so it marshals
+	// as [].
+	d := differ{changes: make([]Change, 0)}
+
+	oldTypes, oldDirectives, oldSchema := definitions(oldDoc)
+	newTypes, newDirectives, newSchema := definitions(newDoc)
+
+	d.schema(oldSchema, newSchema, oldTypes, newTypes, newDoc)
+
+	for _, o := range oldTypes {
+		n, ok := find(newTypes, o.name)
+		if !ok {
+			d.removed(Breaking, TypeRemoved, o.node, o.name, "type %v was removed", o.name)
+			continue
+		}
+		d.types(o.name, o.node, n)
+	}
+	for _, n := range newTypes {
+		if _, ok := find(oldTypes, n.name); !ok {
+			d.add(Safe, TypeAdded, n.node, n.name, "type %v was added", n.name)
+		}
+	}
+
+	for _, o := range oldDirectives {
+		n, ok := find(newDirectives, o.name)
+		if !ok {
+			d.removed(Breaking, DirectiveRemoved, o.node, "@"+o.name, "directive @%v was removed", o.name)
+			continue
+		}
+		d.directives(o.name, o.node.(parse.DirectiveDefNode), n.(parse.DirectiveDefNode))
+	}
+	for _, n := range newDirectives {
+		if _, ok := find(oldDirectives, n.name); !ok {
+			d.add(Safe, DirectiveAdded, n.node, "@"+n.name, "directive @%v was added", n.name)
+		}
+	}
+
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		return d.changes[i].Criticality > d.changes[j].Criticality
+	})
+	return d.changes
+}
@@ -0,0 +1,132 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/diff"
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+func TestDiff(t *testing.T) {
+	tests := map[string]struct {
+		oldSchema       string
+		newSchema       string
+		expectedChanges []string
+	}{
+		"unchanged": {
+			oldSchema:       "type Query {\n  ping(a: Int = 1): [String!]! @resolve\n}",
+			newSchema:       "type Query {\n  ping(a: Int = 1): [String!]! @resolve\n}",
+			expectedChanges: []string{},
+		},
+		"output nullability": {
+			oldSchema: "type Query {\n  a: String\n  b: String!\n  c: [String]\n  d: [String!]\n}",
+			newSchema: "type Query {\n  a: String!\n  b: String\n  c: [String!]\n  d: [String]\n}",
+			expectedChanges: []string{
+				"breaking FIELD_TYPE_CHANGED Query.b",
+				"breaking FIELD_TYPE_CHANGED Query.d",
+				"safe FIELD_TYPE_CHANGED Query.a",
+				"safe FIELD_TYPE_CHANGED Query.c",
+			},
+		},
+		"input nullability": {
+			oldSchema: "input In {\n  a: String\n  b: String!\n}",
+			newSchema: "input In {\n  a: String!\n  b: String\n  c: Int\n  d: Int! = 1\n  e: Int!\n}",
+			expectedChanges: []string{
+				"breaking FIELD_TYPE_CHANGED In.a",
+				"breaking FIELD_ADDED In.e",
+				"dangerous FIELD_ADDED In.c",
+				"dangerous FIELD_ADDED In.d",
+				"safe FIELD_TYPE_CHANGED In.b",
+			},
+		},
+		"arguments": {
+			oldSchema: "type Query {\n  ping(a: Int, b: Int, c: Int!): String\n}",
+			newSchema: "type Query {\n  ping(a: Int!, c: Int, d: Int!, e: Int): String\n}",
+			expectedChanges: []string{
+				"breaking ARG_TYPE_CHANGED Query.ping(a)",
+				"breaking ARG_REMOVED Query.ping(b)",
+				"breaking ARG_ADDED Query.ping(d)",
+				"dangerous ARG_ADDED Query.ping(e)",
+				"safe ARG_TYPE_CHANGED Query.ping(c)",
+			},
+		},
+		"type kind": {
+			oldSchema: "type A {\n  a: Int\n}\n\nscalar B",
+			newSchema: "input A {\n  a: Int\n}\n\nenum B {\n  X\n}",
+			expectedChanges: []string{
+				"breaking TYPE_KIND_CHANGED A",
+				"breaking TYPE_KIND_CHANGED B",
+			},
+		},
+		"deprecation": {
+			oldSchema: "type Query {\n  a: Int\n  b: Int @deprecated\n}",
+			newSchema: "type Query {\n  a: Int @deprecated(reason: \"Use b.\")\n  b: Int\n}",
+			expectedChanges: []string{
+				"safe FIELD_DEPRECATED Query.a",
+				"safe FIELD_UNDEPRECATED Query.b",
+			},
+		},
+		"directive usage": {
+			oldSchema: "type Query {\n  a: Int @aws_api_key\n}",
+			newSchema: "type Query {\n  a: Int @aws_iam\n}",
+			expectedChanges: []string{
+				"dangerous DIRECTIVE_USAGE_REMOVED Query.a",
+				"dangerous DIRECTIVE_USAGE_ADDED Query.a",
+			},
+		},
+		"interfaces": {
+			oldSchema: "interface Node {\n  id: ID\n}\n\ntype A implements Node {\n  id: ID\n}",
+			newSchema: "interface Node {\n  id: ID\n}\n\ninterface Entity {\n  id: ID\n}\n\ntype A implements Entity {\n  id: ID\n}",
+			expectedChanges: []string{
+				"breaking INTERFACE_REMOVED A",
+				"dangerous INTERFACE_ADDED A",
+				"safe TYPE_ADDED Entity",
+			},
+		},
+		"schema": {
+			oldSchema: "type Query {\n  a: Int\n}\n\ntype Mutation {\n  a: Int\n}",
+			newSchema: "type Query {\n  a: Int\n}\n\ntype Other {\n  a: Int\n}\n\nschema {\n  query: Query\n  mutation: Other\n}",
+			expectedChanges: []string{
+				"breaking SCHEMA_OPERATION_TYPE_CHANGED schema.mutation",
+				"breaking TYPE_REMOVED Mutation",
+				"safe TYPE_ADDED Other",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			oldAST := parse.TestParse(t, test.oldSchema)
+			newAST := parse.TestParse(t, test.newSchema)
+
+			changes := make([]string, 0)
+			for _, c := range diff.Diff(oldAST, newAST) {
+				changes = append(changes, c.Criticality.String()+" "+string(c.Type)+" "+c.Path)
+			}
+
+			if diff := cmp.Diff(test.expectedChanges, changes); diff != "" {
+				t.Fatalf("mismatch (-expected,+got) %v", diff)
+			}
+		})
+	}
+}
+
+func TestDiffSide(t *testing.T) {
+	oldAST := parse.TestParse(t, "type A {\n  a: Int\n}\n\ntype B {\n  b: Int\n}")
+	newAST := parse.TestParse(t, "type A {\n  a: Int\n  c: Int\n}")
+
+	changes := make([]string, 0)
+	for _, c := range diff.Diff(oldAST, newAST) {
+		changes = append(changes, c.String())
+	}
+
+	expected := []string{
+		"old 5,1 breaking: type B was removed",
+		"new 3,3 safe: field A.c was added",
+	}
+	if diff := cmp.Diff(expected, changes); diff != "" {
+		t.Fatalf("mismatch (-expected,+got) %v", diff)
+	}
+}
@@ -0,0 +1,62 @@
+package gql
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FieldError reports a problem with the value at Path, such as
+// input.owner.id.
+type FieldError struct {
+	Path    string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// Errors collects the errors found by the generated Validate methods.
+type Errors []error
+
+// Missing records that the required value at path is missing.
+func (e *Errors) Missing(path string) {
+	*e = append(*e, &FieldError{Path: path, Message: "required value is missing"})
+}
+
+// Err returns nil when there are no errors, and the errors otherwise.
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap lets errors.As find each FieldError.
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// Path appends the field name to path.
+func Path(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// Index appends the list index i to path.
+func Index(path string, i int) string {
+	return Path(path, strconv.Itoa(i))
+}
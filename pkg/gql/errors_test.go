@@ -0,0 +1,31 @@
+package gql_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/gql"
+)
+
+func TestErrors(t *testing.T) {
+	var errs gql.Errors
+	if err := errs.Err(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	errs.Missing(gql.Path(gql.Path("", "input"), "owner"))
+	errs.Missing(gql.Index(gql.Path("input", "tags"), 2))
+	err := errs.Err()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	expected := "input.owner: required value is missing; input.tags.2: required value is missing"
+	if err.Error() != expected {
+		t.Fatalf("expected %q, got %q", expected, err.Error())
+	}
+
+	var ferr *gql.FieldError
+	if !errors.As(err, &ferr) || ferr.Path != "input.owner" {
+		t.Fatalf("expected the first field error, got %v", ferr)
+	}
+}
@@ -0,0 +1,56 @@
+package gql
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Fields records which required fields of a decoded JSON object were given
+// a value other than null, which lets generated Validate methods report
+// required fields that Go cannot tell from their zero value. It is held in
+// a string, so generated structs stay comparable and copies share nothing.
+// The zero value is for structs built in Go, which miss nothing.
+type Fields struct {
+	// given lists the given fields, each followed by a NUL, after a
+	// leading NUL that marks the object as decoded.
+	given string
+}
+
+// Decode unmarshals the JSON object d into v and returns which of the
+// required fields it gave. The UnmarshalJSON method of a generated struct
+// passes itself as v, converted to a type without the method, and passes
+// its own name for errors to mention. A null object has no fields to
+// report on.
+func Decode(d []byte, name string, v interface{}, required ...string) (Fields, error) {
+	if err := json.Unmarshal(d, v); err != nil {
+		var terr *json.UnmarshalTypeError
+		if errors.As(err, &terr) && terr.Struct != "" {
+			terr.Struct = name
+		}
+		return Fields{}, err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(d, &raw); err != nil {
+		return Fields{}, err
+	}
+	if raw == nil {
+		return Fields{}, nil
+	}
+	var b strings.Builder
+	b.WriteByte(0)
+	for _, name := range required {
+		if v, ok := raw[name]; ok && !bytes.Equal(bytes.TrimSpace(v), []byte("null")) {
+			b.WriteString(name)
+			b.WriteByte(0)
+		}
+	}
+	return Fields{b.String()}, nil
+}
+
+// Missing reports whether the decoded object lacked the required field
+// name or gave it as null.
+func (fs Fields) Missing(name string) bool {
+	return fs.given != "" && !strings.Contains(fs.given, "\x00"+name+"\x00")
+}
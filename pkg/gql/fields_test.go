@@ -0,0 +1,47 @@
+package gql_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/gql"
+)
+
+func TestFields(t *testing.T) {
+	var built gql.Fields
+	if built.Missing("id") {
+		t.Fatal("expected structs built in Go to miss nothing")
+	}
+
+	type plain struct {
+		ID   string  `json:"id"`
+		Name *string `json:"name"`
+	}
+	var v plain
+	fs, err := gql.Decode([]byte(`{"id": "1", "name": null}`), "User", &v, "id", "name", "owner")
+	if err != nil {
+		t.Fatalf("failed to decode %v", err)
+	}
+	if v.ID != "1" {
+		t.Fatalf("expected the object to be decoded, got %v", v)
+	}
+	for name, missing := range map[string]bool{"id": false, "name": true, "owner": true} {
+		if fs.Missing(name) != missing {
+			t.Fatalf("expected %v missing to be %v", name, missing)
+		}
+	}
+
+	same, _ := gql.Decode([]byte(`{"name": "a", "id": "2"}`), "User", &v, "id", "owner")
+	if fs != same {
+		t.Fatalf("expected objects giving the same required fields to compare equal, got %v and %v", fs, same)
+	}
+
+	fs, err = gql.Decode([]byte(`null`), "User", &v, "id")
+	if err != nil || fs.Missing("id") {
+		t.Fatalf("expected a null object to miss nothing, got %v %v", fs, err)
+	}
+	_, err = gql.Decode([]byte(`{"id": 1}`), "User", &v, "id")
+	if err == nil || !strings.Contains(err.Error(), "Go struct field User.id") {
+		t.Fatalf("expected the error to name the struct, got %v", err)
+	}
+}
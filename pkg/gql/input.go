@@ -0,0 +1,103 @@
+package gql
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Input holds an input field that may be absent, null or set to a value,
+// so that updates can tell "clear this field" from "leave it alone". The
+// zero value is absent.
+type Input[T any] struct {
+	Value T
+	// Present is set when the field was given, even as null.
+	Present bool
+	// Valid is set when the field was given a value other than null.
+	Valid bool
+}
+
+// Set returns an Input holding v.
+func Set[T any](v T) Input[T] {
+	return Input[T]{Value: v, Present: true, Valid: true}
+}
+
+// Null returns an Input that was explicitly given as null.
+func Null[T any]() Input[T] {
+	return Input[T]{Present: true}
+}
+
+// IsNull reports whether the field was given as null.
+func (i Input[T]) IsNull() bool {
+	return i.Present && !i.Valid
+}
+
+// Get returns the value and whether one was given.
+func (i Input[T]) Get() (T, bool) {
+	return i.Value, i.Valid
+}
+
+// Or returns the value, or def when the field is absent or null.
+func (i Input[T]) Or(def T) T {
+	if !i.Valid {
+		return def
+	}
+	return i.Value
+}
+
+// Optional drops the difference between absent and null.
+func (i Input[T]) Optional() Optional[T] {
+	return Optional[T]{Value: i.Value, Valid: i.Valid}
+}
+
+// Patch applies the field to dst: absent leaves it alone, null sets it to
+// the zero value and a value replaces it.
+func (i Input[T]) Patch(dst *T) {
+	if !i.Present {
+		return
+	}
+	*dst = i.Value
+}
+
+// PatchPointer applies the field to dst like Patch, but null sets it to
+// nil.
+func (i Input[T]) PatchPointer(dst **T) {
+	if !i.Present {
+		return
+	}
+	if !i.Valid {
+		*dst = nil
+		return
+	}
+	v := i.Value
+	*dst = &v
+}
+
+// IsZero reports whether the field is absent, which lets encoding/json
+// omit it with the omitzero option.
+func (i Input[T]) IsZero() bool {
+	return !i.Present
+}
+
+// MarshalJSON writes null for absent fields too, since a value cannot omit
+// itself; fields tagged with omitzero, as generated ones are, stay absent.
+func (i Input[T]) MarshalJSON() ([]byte, error) {
+	if !i.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(i.Value)
+}
+
+// UnmarshalJSON is only called for fields present in the JSON, so absent
+// fields keep their zero value.
+func (i *Input[T]) UnmarshalJSON(d []byte) error {
+	if bytes.Equal(bytes.TrimSpace(d), []byte("null")) {
+		*i = Null[T]()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(d, &v); err != nil {
+		return err
+	}
+	*i = Set(v)
+	return nil
+}
@@ -0,0 +1,110 @@
+package gql_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/gql"
+)
+
+// update is tagged like the generated types, so absent fields survive a
+// round trip.
+type update struct {
+	Name  gql.Input[string]   `json:"name,omitzero"`
+	Count gql.Input[int]      `json:"count,omitzero"`
+	Tags  gql.Input[[]string] `json:"tags,omitzero"`
+}
+
+func TestInputJSON(t *testing.T) {
+	tests := map[string]struct {
+		json     string
+		expected update
+	}{
+		"absent": {
+			json:     `{}`,
+			expected: update{},
+		},
+		"null": {
+			json:     `{"name":null,"count":null,"tags":null}`,
+			expected: update{Name: gql.Null[string](), Count: gql.Null[int](), Tags: gql.Null[[]string]()},
+		},
+		"values": {
+			json:     `{"name":"","count":0,"tags":["a"]}`,
+			expected: update{Name: gql.Set(""), Count: gql.Set(0), Tags: gql.Set([]string{"a"})},
+		},
+		"mixed": {
+			json:     `{"name":"a","tags":null}`,
+			expected: update{Name: gql.Set("a"), Tags: gql.Null[[]string]()},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got update
+			if err := json.Unmarshal([]byte(test.json), &got); err != nil {
+				t.Fatalf("failed to unmarshal %v", err)
+			}
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Fatalf("mismatch (-expected,+got) %v", diff)
+			}
+			d, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("failed to marshal %v", err)
+			}
+			if diff := cmp.Diff(test.json, string(d)); diff != "" {
+				t.Fatalf("round trip mismatch (-expected,+got) %v", diff)
+			}
+		})
+	}
+}
+
+func TestInputPatch(t *testing.T) {
+	name, count := "old", 3
+	countPtr := &count
+
+	var u update
+	if err := json.Unmarshal([]byte(`{"count":null}`), &u); err != nil {
+		t.Fatalf("failed to unmarshal %v", err)
+	}
+	u.Name.Patch(&name)
+	u.Count.PatchPointer(&countPtr)
+	if name != "old" || countPtr != nil {
+		t.Fatalf("expected name kept and count cleared got %v %v", name, countPtr)
+	}
+
+	gql.Set("new").Patch(&name)
+	gql.Set(5).PatchPointer(&countPtr)
+	if name != "new" || countPtr == nil || *countPtr != 5 {
+		t.Fatalf("expected name and count set got %v %v", name, countPtr)
+	}
+
+	gql.Null[string]().Patch(&name)
+	if name != "" {
+		t.Fatalf("expected name cleared got %v", name)
+	}
+}
+
+func TestInputHelpers(t *testing.T) {
+	absent, null, set := gql.Input[int]{}, gql.Null[int](), gql.Set(2)
+	if absent.IsNull() || !null.IsNull() || set.IsNull() {
+		t.Fatalf("unexpected IsNull")
+	}
+	if !absent.IsZero() || null.IsZero() || set.IsZero() {
+		t.Fatalf("unexpected IsZero")
+	}
+	if absent.Or(1) != 1 || null.Or(1) != 1 || set.Or(1) != 2 {
+		t.Fatalf("unexpected Or")
+	}
+	if diff := cmp.Diff(gql.Some(2), set.Optional()); diff != "" {
+		t.Fatalf("mismatch (-expected,+got) %v", diff)
+	}
+	d, err := json.Marshal(update{Name: gql.Set("a"), Count: null})
+	if err != nil {
+		t.Fatalf("failed to marshal %v", err)
+	}
+	if diff := cmp.Diff(`{"name":"a","count":null}`, string(d)); diff != "" {
+		t.Fatalf("mismatch (-expected,+got) %v", diff)
+	}
+}
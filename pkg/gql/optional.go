@@ -0,0 +1,50 @@
+package gql
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Optional holds a value of a nullable GraphQL type. The zero value is
+// null.
+type Optional[T any] struct {
+	Value T
+	Valid bool
+}
+
+// Some returns an Optional holding v.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{Value: v, Valid: true}
+}
+
+// Get returns the value and whether it is not null.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Valid
+}
+
+// Or returns the value, or def when it is null.
+func (o Optional[T]) Or(def T) T {
+	if !o.Valid {
+		return def
+	}
+	return o.Value
+}
+
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value)
+}
+
+func (o *Optional[T]) UnmarshalJSON(d []byte) error {
+	if bytes.Equal(bytes.TrimSpace(d), []byte("null")) {
+		*o = Optional[T]{}
+		return nil
+	}
+	if err := json.Unmarshal(d, &o.Value); err != nil {
+		return err
+	}
+	o.Valid = true
+	return nil
+}
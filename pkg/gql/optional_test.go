@@ -0,0 +1,81 @@
+package gql_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/gql"
+)
+
+type optionals struct {
+	Name  gql.Optional[string]   `json:"name"`
+	Count gql.Optional[int]      `json:"count"`
+	Tags  []gql.Optional[string] `json:"tags"`
+}
+
+func TestOptionalJSON(t *testing.T) {
+	tests := map[string]struct {
+		json     string
+		expected optionals
+		output   string
+	}{
+		"values": {
+			json:     `{"name":"a","count":0,"tags":["b",null]}`,
+			expected: optionals{Name: gql.Some("a"), Count: gql.Some(0), Tags: []gql.Optional[string]{gql.Some("b"), {}}},
+			output:   `{"name":"a","count":0,"tags":["b",null]}`,
+		},
+		"nulls": {
+			json:     `{"name":null,"count":null,"tags":null}`,
+			expected: optionals{},
+			output:   `{"name":null,"count":null,"tags":null}`,
+		},
+		"absent": {
+			json:     `{}`,
+			expected: optionals{},
+			output:   `{"name":null,"count":null,"tags":null}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got optionals
+			if err := json.Unmarshal([]byte(test.json), &got); err != nil {
+				t.Fatalf("failed to unmarshal %v", err)
+			}
+			if diff := cmp.Diff(test.expected, got); diff != "" {
+				t.Fatalf("mismatch (-expected,+got) %v", diff)
+			}
+			d, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("failed to marshal %v", err)
+			}
+			if diff := cmp.Diff(test.output, string(d)); diff != "" {
+				t.Fatalf("mismatch (-expected,+got) %v", diff)
+			}
+		})
+	}
+}
+
+func TestOptionalInvalid(t *testing.T) {
+	var o gql.Optional[int]
+	if err := json.Unmarshal([]byte(`"a"`), &o); err == nil {
+		t.Fatalf("expected error unmarshaling a string into an int")
+	}
+	if o.Valid {
+		t.Fatalf("expected a failed unmarshal to stay null")
+	}
+}
+
+func TestOptionalOr(t *testing.T) {
+	if got := (gql.Optional[int]{}).Or(5); got != 5 {
+		t.Fatalf("expected default 5 got %v", got)
+	}
+	if got := gql.Some(3).Or(5); got != 3 {
+		t.Fatalf("expected 3 got %v", got)
+	}
+	if v, ok := gql.Some("x").Get(); !ok || v != "x" {
+		t.Fatalf("expected x got %v %v", v, ok)
+	}
+}
@@ -0,0 +1,394 @@
+package introspection
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+type Response struct {
+	Data   *Data           `json:"data"`
+	Errors []ResponseError `json:"errors,omitempty"`
+}
+
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+type Data struct {
+	Schema *Schema `json:"__schema"`
+}
+
+type Schema struct {
+	Description      *string     `json:"description,omitempty"`
+	QueryType        *TypeName   `json:"queryType"`
+	MutationType     *TypeName   `json:"mutationType"`
+	SubscriptionType *TypeName   `json:"subscriptionType"`
+	Types            []Type      `json:"types"`
+	Directives       []Directive `json:"directives"`
+}
+
+type TypeName struct {
+	Name string `json:"name"`
+}
+
+type Type struct {
+	Kind           string       `json:"kind"`
+	Name           *string      `json:"name"`
+	Description    *string      `json:"description"`
+	SpecifiedByURL *string      `json:"specifiedByURL,omitempty"`
+	Fields         []Field      `json:"fields"`
+	InputFields    []InputValue `json:"inputFields"`
+	Interfaces     []TypeRef    `json:"interfaces"`
+	EnumValues     []EnumValue  `json:"enumValues"`
+	PossibleTypes  []TypeRef    `json:"possibleTypes"`
+}
+
+type TypeRef struct {
+	Kind   string   `json:"kind"`
+	Name   *string  `json:"name"`
+	OfType *TypeRef `json:"ofType"`
+}
+
+type Field struct {
+	Name              string       `json:"name"`
+	Description       *string      `json:"description"`
+	Args              []InputValue `json:"args"`
+	Type              TypeRef      `json:"type"`
+	IsDeprecated      bool         `json:"isDeprecated"`
+	DeprecationReason *string      `json:"deprecationReason"`
+}
+
+type InputValue struct {
+	Name              string  `json:"name"`
+	Description       *string `json:"description"`
+	Type              TypeRef `json:"type"`
+	DefaultValue      *string `json:"defaultValue"`
+	IsDeprecated      bool    `json:"isDeprecated"`
+	DeprecationReason *string `json:"deprecationReason"`
+}
+
+type EnumValue struct {
+	Name              string  `json:"name"`
+	Description       *string `json:"description"`
+	IsDeprecated      bool    `json:"isDeprecated"`
+	DeprecationReason *string `json:"deprecationReason"`
+}
+
+type Directive struct {
+	Name         string       `json:"name"`
+	Description  *string      `json:"description"`
+	IsRepeatable bool         `json:"isRepeatable"`
+	Locations    []string     `json:"locations"`
+	Args         []InputValue `json:"args"`
+}
+
+const DefaultDeprecationReason = "No longer supported"
+
+var builtinTypes = map[string]bool{
+	"String":  true,
+	"Int":     true,
+	"Float":   true,
+	"Boolean": true,
+	"ID":      true,
+}
+
+var builtinDirectives = map[string]bool{
+	"skip":        true,
+	"include":     true,
+	"deprecated":  true,
+	"specifiedBy": true,
+}
+
+func str(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// Decode reads an introspection result. Both the full response envelope
+// ({"data":{"__schema":...}}) and the bare {"__schema":...} object are
+// accepted.
+func Decode(data []byte) (*Schema, error) {
+	var r Response
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to decode introspection result: %v", err)
+	}
+	if r.Data != nil && r.Data.Schema != nil {
+		return r.Data.Schema, nil
+	}
+
+	var d Data
+	if err := json.Unmarshal(data, &d); err == nil && d.Schema != nil {
+		return d.Schema, nil
+	}
+
+	if len(r.Errors) > 0 {
+		messages := make([]string, len(r.Errors))
+		for i, e := range r.Errors {
+			messages[i] = e.Message
+		}
+		return nil, fmt.Errorf("introspection result has errors: %v", strings.Join(messages, "; "))
+	}
+	return nil, errors.New("introspection result has no __schema")
+}
+
+func typeNode(ref TypeRef) (parse.Node, error) {
+	var tn parse.TypeNode
+	if ref.Kind == "NON_NULL" {
+		if ref.OfType == nil {
+			return nil, errors.New("NON_NULL type reference without ofType")
+		}
+		tn.Required = true
+		ref = *ref.OfType
+	}
+	if ref.Kind == "LIST" {
+		if ref.OfType == nil {
+			return nil, errors.New("LIST type reference without ofType")
+		}
+		tn.Multiple = true
+		ref = *ref.OfType
+		if ref.Kind == "NON_NULL" {
+			if ref.OfType == nil {
+				return nil, errors.New("NON_NULL type reference without ofType")
+			}
+			tn.NonNullElements = true
+			ref = *ref.OfType
+		}
+	}
+	if ref.Kind == "LIST" || ref.Kind == "NON_NULL" {
+		return nil, errors.New("nested list types are not supported")
+	}
+	if ref.Name == nil {
+		return nil, fmt.Errorf("%v type reference without name", ref.Kind)
+	}
+	tn.Name = *ref.Name
+	return tn, nil
+}
+
+func deprecated(isDeprecated bool, reason *string) []parse.Node {
+	if !isDeprecated {
+		return nil
+	}
+	dn := parse.DirectiveNode{Name: "deprecated"}
+	if r := str(reason); r != "" && r != DefaultDeprecationReason {
+		dn.Arguments = []parse.Node{
+			parse.ArgumentNode{
+				Name:  "reason",
+				Value: parse.ValueNode{Kind: parse.StringValue, Value: r},
+			},
+		}
+	}
+	return []parse.Node{dn}
+}
+
+func defaultValue(v *string) (parse.Node, error) {
+	if v == nil {
+		return nil, nil
+	}
+	p := parse.New(parse.NewLexer(*v))
+	n, err := p.ParseValue()
+	if err != nil {
+		return nil, fmt.Errorf("invalid default value %v: %v", *v, err.Error)
+	}
+	return n, nil
+}
+
+func params(ivs []InputValue) ([]parse.Node, error) {
+	if len(ivs) == 0 {
+		return nil, nil
+	}
+	nodes := make([]parse.Node, len(ivs))
+	for i, iv := range ivs {
+		tn, err := typeNode(iv.Type)
+		if err != nil {
+			return nil, fmt.Errorf("argument %v: %v", iv.Name, err)
+		}
+		dv, err := defaultValue(iv.DefaultValue)
+		if err != nil {
+			return nil, fmt.Errorf("argument %v: %v", iv.Name, err)
+		}
+		nodes[i] = parse.ParamNode{
+			Description:  str(iv.Description),
+			Name:         iv.Name,
+			Type:         tn,
+			DefaultValue: dv,
+			Directives:   deprecated(iv.IsDeprecated, iv.DeprecationReason),
+		}
+	}
+	return nodes, nil
+}
+
+func fields(t Type) ([]parse.Node, error) {
+	nodes := make([]parse.Node, 0)
+	for _, f := range t.Fields {
+		tn, err := typeNode(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %v: %v", f.Name, err)
+		}
+		ps, err := params(f.Args)
+		if err != nil {
+			return nil, fmt.Errorf("field %v: %v", f.Name, err)
+		}
+		nodes = append(nodes, parse.FieldNode{
+			Description: str(f.Description),
+			Name:        f.Name,
+			Type:        tn,
+			Params:      ps,
+			Directives:  deprecated(f.IsDeprecated, f.DeprecationReason),
+		})
+	}
+	for _, iv := range t.InputFields {
+		tn, err := typeNode(iv.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %v: %v", iv.Name, err)
+		}
+		dv, err := defaultValue(iv.DefaultValue)
+		if err != nil {
+			return nil, fmt.Errorf("field %v: %v", iv.Name, err)
+		}
+		nodes = append(nodes, parse.FieldNode{
+			Description:  str(iv.Description),
+			Name:         iv.Name,
+			Type:         tn,
+			DefaultValue: dv,
+			Directives:   deprecated(iv.IsDeprecated, iv.DeprecationReason),
+		})
+	}
+	return nodes, nil
+}
+
+func names(refs []TypeRef) []string {
+	if len(refs) == 0 {
+		return nil
+	}
+	ns := make([]string, len(refs))
+	for i, ref := range refs {
+		ns[i] = str(ref.Name)
+	}
+	return ns
+}
+
+func definition(t Type) (parse.Node, error) {
+	name := str(t.Name)
+	description := str(t.Description)
+
+	switch t.Kind {
+	case "SCALAR":
+		sn := parse.ScalarDefNode{Description: description, Name: name}
+		if t.SpecifiedByURL != nil {
+			sn.Directives = []parse.Node{
+				parse.DirectiveNode{
+					Name: "specifiedBy",
+					Arguments: []parse.Node{
+						parse.ArgumentNode{
+							Name:  "url",
+							Value: parse.ValueNode{Kind: parse.StringValue, Value: *t.SpecifiedByURL},
+						},
+					},
+				},
+			}
+		}
+		return sn, nil
+	case "OBJECT", "INTERFACE", "INPUT_OBJECT":
+		fs, err := fields(t)
+		if err != nil {
+			return nil, err
+		}
+		return parse.TypeDefNode{
+			Description: description,
+			Name:        name,
+			Fields:      fs,
+			Input:       t.Kind == "INPUT_OBJECT",
+			Interface:   t.Kind == "INTERFACE",
+			Interfaces:  names(t.Interfaces),
+		}, nil
+	case "UNION":
+		return parse.UnionDefNode{
+			Description: description,
+			Name:        name,
+			Types:       names(t.PossibleTypes),
+		}, nil
+	case "ENUM":
+		values := make([]parse.Node, len(t.EnumValues))
+		for i, ev := range t.EnumValues {
+			values[i] = parse.EnumValueNode{
+				Description: str(ev.Description),
+				Name:        ev.Name,
+				Directives:  deprecated(ev.IsDeprecated, ev.DeprecationReason),
+			}
+		}
+		return parse.EnumDefNode{
+			Description: description,
+			Name:        name,
+			Values:      values,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown kind %v", t.Kind)
+	}
+}
+
+// ToDocument converts an introspection result into the same document the
+// parser would produce for the equivalent SDL. Built in scalars, directives
+// and introspection types are left out.
+func ToDocument(s *Schema) (parse.DocumentNode, error) {
+	var doc parse.DocumentNode
+
+	for _, d := range s.Directives {
+		if builtinDirectives[d.Name] {
+			continue
+		}
+		ps, err := params(d.Args)
+		if err != nil {
+			return doc, fmt.Errorf("directive @%v: %v", d.Name, err)
+		}
+		doc.Definitions = append(doc.Definitions, parse.DirectiveDefNode{
+			Description: str(d.Description),
+			Name:        d.Name,
+			Params:      ps,
+			Repeatable:  d.IsRepeatable,
+			Targets:     d.Locations,
+		})
+	}
+
+	for _, t := range s.Types {
+		name := str(t.Name)
+		if builtinTypes[name] || strings.HasPrefix(name, "__") {
+			continue
+		}
+		n, err := definition(t)
+		if err != nil {
+			return doc, fmt.Errorf("type %v: %v", name, err)
+		}
+		doc.Definitions = append(doc.Definitions, n)
+	}
+
+	operations := make([]parse.Node, 0)
+	for _, op := range []struct {
+		name string
+		t    *TypeName
+	}{
+		{"query", s.QueryType},
+		{"mutation", s.MutationType},
+		{"subscription", s.SubscriptionType},
+	} {
+		if op.t != nil {
+			operations = append(operations, parse.FieldNode{
+				Name: op.name,
+				Type: parse.TypeNode{Name: op.t.Name},
+			})
+		}
+	}
+	if len(operations) > 0 {
+		doc.Definitions = append(doc.Definitions, parse.SchemaNode{
+			Description: str(s.Description),
+			Fields:      operations,
+		})
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,114 @@
+package introspection_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/introspection"
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+func TestToDocument(t *testing.T) {
+	tests := map[string]struct {
+		result        string
+		expectedSDL   string
+		expectedError string
+	}{
+		"bare schema": {
+			result:      `{"__schema":{"queryType":{"name":"Query"},"types":[{"kind":"OBJECT","name":"Query","fields":[{"name":"ping","args":[],"type":{"kind":"NON_NULL","ofType":{"kind":"LIST","ofType":{"kind":"NON_NULL","ofType":{"kind":"SCALAR","name":"String"}}}}}],"interfaces":[]}],"directives":[]}}`,
+			expectedSDL: "type Query {\n  ping: [String!]!\n}\n\nschema {\n  query: Query\n}\n",
+		},
+		"nested list": {
+			result:        `{"data":{"__schema":{"types":[{"kind":"OBJECT","name":"Query","fields":[{"name":"grid","args":[],"type":{"kind":"LIST","ofType":{"kind":"LIST","ofType":{"kind":"SCALAR","name":"Int"}}}}]}]}}}`,
+			expectedError: "type Query: field grid: nested list types are not supported",
+		},
+		"bad default": {
+			result:        `{"data":{"__schema":{"types":[{"kind":"INPUT_OBJECT","name":"In","inputFields":[{"name":"a","type":{"kind":"SCALAR","name":"Int"},"defaultValue":"{"}]}]}}}`,
+			expectedError: "type In: field a: invalid default value {: expected right curly token got end of file token",
+		},
+		"no schema": {
+			result:        `{"data":null}`,
+			expectedError: "introspection result has no __schema",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var sdl, errMessage string
+			s, err := introspection.Decode([]byte(test.result))
+			if err == nil {
+				var doc parse.DocumentNode
+				doc, err = introspection.ToDocument(s)
+				sdl = parse.Print(doc)
+			}
+			if err != nil {
+				sdl = ""
+				errMessage = err.Error()
+			}
+
+			if diff := cmp.Diff(test.expectedError, errMessage); diff != "" {
+				t.Fatalf("error mismatch (expected, got) %v", diff)
+			}
+			if diff := cmp.Diff(test.expectedSDL, sdl); diff != "" {
+				t.Fatalf("mismatch (expected, got) %v", diff)
+			}
+		})
+	}
+}
+
+func TestFromDocument(t *testing.T) {
+	schema := "directive @auth(groups: [String!] = [\"admin\"]) repeatable on FIELD_DEFINITION\n\n" +
+		"interface Node {\n  id: ID!\n}\n\n" +
+		"\"A user.\"\ntype User implements Node {\n  id: ID!\n  name: String @deprecated\n  tags(first: Int = 1): [Tag!]\n}\n\n" +
+		"enum Tag {\n  A\n  B @deprecated(reason: \"Use A.\")\n}\n\n" +
+		"scalar URL @specifiedBy(url: \"https://tools.ietf.org/html/rfc3986\")\n\n" +
+		"union Result = User\n\n" +
+		"input UserInput {\n  name: String = \"x\"\n}\n\n" +
+		"type Query {\n  node(id: ID!): Node\n  search(input: UserInput): [Result]!\n}\n\n" +
+		"schema {\n  query: Query\n}\n"
+
+	ast := parse.TestParse(t, schema)
+
+	s, err := introspection.FromDocument(ast, introspection.Options{})
+	if err != nil {
+		t.Fatalf("failed to build introspection result: %v", err)
+	}
+	doc, err := introspection.ToDocument(s)
+	if err != nil {
+		t.Fatalf("failed to convert introspection result: %v", err)
+	}
+
+	if diff := cmp.Diff(schema, parse.Print(doc)); diff != "" {
+		t.Fatalf("mismatch (expected, got) %v", diff)
+	}
+}
+
+func TestFromDocumentUnknownTypes(t *testing.T) {
+	ast := parse.TestParse(t, "type Query {\n  t: AWSDateTime\n  u(e: AWSEmail): Missing\n}\n")
+
+	if _, err := introspection.FromDocument(ast, introspection.Options{}); err == nil || err.Error() != "unknown types AWSDateTime, AWSEmail, Missing" {
+		t.Fatalf("expected unknown types error, got %v", err)
+	}
+	if _, err := introspection.FromDocument(ast, introspection.Options{AWSScalars: true}); err == nil || err.Error() != "unknown types Missing" {
+		t.Fatalf("expected unknown types error, got %v", err)
+	}
+
+	ast = parse.TestParse(t, "scalar AWSJSON\n\ntype Query {\n  t: AWSDateTime\n  j: AWSJSON\n}\n")
+	s, err := introspection.FromDocument(ast, introspection.Options{AWSScalars: true})
+	if err != nil {
+		t.Fatalf("failed to build introspection result: %v", err)
+	}
+	kinds := make(map[string]string)
+	count := 0
+	for _, typ := range s.Types {
+		if typ.Name != nil && strings.HasPrefix(*typ.Name, "AWS") {
+			kinds[*typ.Name] = typ.Kind
+			count++
+		}
+	}
+	if kinds["AWSDateTime"] != "SCALAR" || count != len(introspection.AWSScalars) {
+		t.Fatalf("expected each AWS scalar to be declared once, got %v", kinds)
+	}
+}
@@ -0,0 +1,476 @@
+package introspection
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+const builtinSDL = `"The ` + "`String`" + ` scalar type represents textual data, represented as UTF-8 character sequences. The String type is most often used by GraphQL to represent free-form human-readable text."
+scalar String
+
+"The ` + "`Int`" + ` scalar type represents non-fractional signed whole numeric values. Int can represent values between -(2^31) and 2^31 - 1."
+scalar Int
+
+"The ` + "`Float`" + ` scalar type represents signed double-precision fractional values as specified by [IEEE 754](https://en.wikipedia.org/wiki/IEEE_floating_point)."
+scalar Float
+
+"The ` + "`Boolean`" + ` scalar type represents ` + "`true` or `false`" + `."
+scalar Boolean
+
+"The ` + "`ID`" + ` scalar type represents a unique identifier, often used to refetch an object or as key for a cache. The ID type appears in a JSON response as a String; however, it is not intended to be human-readable. When expected as an input type, any string (such as ` + "`\\\"4\\\"`" + `) or integer (such as ` + "`4`" + `) input value will be accepted as an ID."
+scalar ID
+
+"A GraphQL Schema defines the capabilities of a GraphQL server. It exposes all available types and directives on the server, as well as the entry points for query, mutation, and subscription operations."
+type __Schema {
+  description: String
+  "A list of all types supported by this server."
+  types: [__Type!]!
+  "The type that query operations will be rooted at."
+  queryType: __Type!
+  "If this server supports mutation, the type that mutation operations will be rooted at."
+  mutationType: __Type
+  "If this server support subscription, the type that subscription operations will be rooted at."
+  subscriptionType: __Type
+  "A list of all directives supported by this server."
+  directives: [__Directive!]!
+}
+
+"The fundamental unit of any GraphQL Schema is the type. There are many kinds of types in GraphQL as represented by the ` + "`__TypeKind`" + ` enum."
+type __Type {
+  kind: __TypeKind!
+  name: String
+  description: String
+  specifiedByURL: String
+  fields(includeDeprecated: Boolean = false): [__Field!]
+  interfaces: [__Type!]
+  possibleTypes: [__Type!]
+  enumValues(includeDeprecated: Boolean = false): [__EnumValue!]
+  inputFields(includeDeprecated: Boolean = false): [__InputValue!]
+  ofType: __Type
+}
+
+"An enum describing what kind of type a given ` + "`__Type`" + ` is."
+enum __TypeKind {
+  "Indicates this type is a scalar."
+  SCALAR
+  "Indicates this type is an object. ` + "`fields` and `interfaces`" + ` are valid fields."
+  OBJECT
+  "Indicates this type is an interface. ` + "`fields`, `interfaces`, and `possibleTypes`" + ` are valid fields."
+  INTERFACE
+  "Indicates this type is a union. ` + "`possibleTypes`" + ` is a valid field."
+  UNION
+  "Indicates this type is an enum. ` + "`enumValues`" + ` is a valid field."
+  ENUM
+  "Indicates this type is an input object. ` + "`inputFields`" + ` is a valid field."
+  INPUT_OBJECT
+  "Indicates this type is a list. ` + "`ofType`" + ` is a valid field."
+  LIST
+  "Indicates this type is a non-null. ` + "`ofType`" + ` is a valid field."
+  NON_NULL
+}
+
+"Object and Interface types are described by a list of Fields, each of which has a name, potentially a list of arguments, and a return type."
+type __Field {
+  name: String!
+  description: String
+  args(includeDeprecated: Boolean = false): [__InputValue!]!
+  type: __Type!
+  isDeprecated: Boolean!
+  deprecationReason: String
+}
+
+"Arguments provided to Fields or Directives and the input fields of an InputObject are represented as Input Values which describe their type and optionally a default value."
+type __InputValue {
+  name: String!
+  description: String
+  type: __Type!
+  "A GraphQL-formatted string representing the default value for this input value."
+  defaultValue: String
+  isDeprecated: Boolean!
+  deprecationReason: String
+}
+
+"One possible value for a given Enum. Enum values are unique values, not a placeholder for a string or numeric value. However an Enum value is returned in a JSON response as a string."
+type __EnumValue {
+  name: String!
+  description: String
+  isDeprecated: Boolean!
+  deprecationReason: String
+}
+
+"A Directive provides a way to describe alternate runtime execution and type validation behavior in a GraphQL document."
+type __Directive {
+  name: String!
+  description: String
+  isRepeatable: Boolean!
+  locations: [__DirectiveLocation!]!
+  args(includeDeprecated: Boolean = false): [__InputValue!]!
+}
+
+"A Directive can be adjacent to many parts of the GraphQL language, a __DirectiveLocation describes one such possible adjacencies."
+enum __DirectiveLocation {
+  QUERY
+  MUTATION
+  SUBSCRIPTION
+  FIELD
+  FRAGMENT_DEFINITION
+  FRAGMENT_SPREAD
+  INLINE_FRAGMENT
+  VARIABLE_DEFINITION
+  SCHEMA
+  SCALAR
+  OBJECT
+  FIELD_DEFINITION
+  ARGUMENT_DEFINITION
+  INTERFACE
+  UNION
+  ENUM
+  ENUM_VALUE
+  INPUT_OBJECT
+  INPUT_FIELD_DEFINITION
+}
+
+"Directs the executor to include this field or fragment only when the ` + "`if`" + ` argument is true."
+directive @include("Included when true." if: Boolean!) on FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT
+
+"Directs the executor to skip this field or fragment when the ` + "`if`" + ` argument is true."
+directive @skip("Skipped when true." if: Boolean!) on FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT
+
+"Marks an element of a GraphQL schema as no longer supported."
+directive @deprecated("Explains why this element was deprecated, usually also including a suggestion for how to access supported similar data. Formatted using the Markdown syntax, as specified by [CommonMark](https://commonmark.org/)." reason: String = "No longer supported") on FIELD_DEFINITION | ARGUMENT_DEFINITION | INPUT_FIELD_DEFINITION | ENUM_VALUE
+
+"Exposes a URL that specifies the behavior of this scalar."
+directive @specifiedBy("The URL that specifies the behavior of this scalar." url: String!) on SCALAR
+`
+
+var builtins = func() parse.DocumentNode {
+	p := parse.New(parse.NewLexer(builtinSDL))
+	n, err := p.Parse()
+	if err != nil {
+		panic(fmt.Sprintf("failed to parse builtin schema: %v (%v)", err.Error, err.Token))
+	}
+	return n.(parse.DocumentNode)
+}()
+
+func strPtr(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// AWSScalars are the scalars that AppSync provides without a definition in
+// the schema.
+var AWSScalars = []string{"AWSDate", "AWSTime", "AWSDateTime", "AWSTimestamp", "AWSEmail", "AWSJSON", "AWSURL", "AWSPhone", "AWSIPAddress"}
+
+// Options configure FromDocument.
+type Options struct {
+	// AWSScalars declares the AppSync scalars that the schema uses but does
+	// not define.
+	AWSScalars bool
+}
+
+type schemaBuilder struct {
+	kinds map[string]string
+	// unknown collects the referenced types that are not defined.
+	unknown map[string]bool
+}
+
+func kindOf(n parse.Node) (string, string) {
+	switch dn := n.(type) {
+	case parse.TypeDefNode:
+		switch {
+		case dn.Input:
+			return dn.Name, "INPUT_OBJECT"
+		case dn.Interface:
+			return dn.Name, "INTERFACE"
+		default:
+			return dn.Name, "OBJECT"
+		}
+	case parse.EnumDefNode:
+		return dn.Name, "ENUM"
+	case parse.ScalarDefNode:
+		return dn.Name, "SCALAR"
+	case parse.UnionDefNode:
+		return dn.Name, "UNION"
+	}
+	return "", ""
+}
+
+func (b schemaBuilder) named(name string) TypeRef {
+	kind, ok := b.kinds[name]
+	if !ok {
+		b.unknown[name] = true
+	}
+	return TypeRef{Kind: kind, Name: &name}
+}
+
+func (b schemaBuilder) typeRef(n parse.Node) TypeRef {
+	tn := n.(parse.TypeNode)
+	ref := b.named(tn.Name)
+	if tn.Multiple {
+		if tn.NonNullElements {
+			ref = wrap("NON_NULL", ref)
+		}
+		ref = wrap("LIST", ref)
+	}
+	if tn.Required {
+		ref = wrap("NON_NULL", ref)
+	}
+	return ref
+}
+
+func wrap(kind string, ofType TypeRef) TypeRef {
+	return TypeRef{Kind: kind, OfType: &ofType}
+}
+
+func (b schemaBuilder) typeRefs(names []string) []TypeRef {
+	refs := make([]TypeRef, len(names))
+	for i, name := range names {
+		refs[i] = b.named(name)
+	}
+	return refs
+}
+
+func deprecation(directives []parse.Node) (bool, *string) {
+	for _, n := range directives {
+		dn := n.(parse.DirectiveNode)
+		if dn.Name != "deprecated" {
+			continue
+		}
+		reason := DefaultDeprecationReason
+		for _, a := range dn.Arguments {
+			an := a.(parse.ArgumentNode)
+			if vn, ok := an.Value.(parse.ValueNode); ok && an.Name == "reason" && vn.Kind == parse.StringValue {
+				reason = vn.Value
+			}
+		}
+		return true, &reason
+	}
+	return false, nil
+}
+
+func (b schemaBuilder) inputValue(description string, name string, t parse.Node, dv parse.Node, directives []parse.Node) InputValue {
+	iv := InputValue{
+		Name:        name,
+		Description: strPtr(description),
+		Type:        b.typeRef(t),
+	}
+	if dv != nil {
+		v := parse.PrintValue(dv)
+		iv.DefaultValue = &v
+	}
+	iv.IsDeprecated, iv.DeprecationReason = deprecation(directives)
+	return iv
+}
+
+func (b schemaBuilder) args(params []parse.Node) []InputValue {
+	ivs := make([]InputValue, len(params))
+	for i, n := range params {
+		pn := n.(parse.ParamNode)
+		ivs[i] = b.inputValue(pn.Description, pn.Name, pn.Type, pn.DefaultValue, pn.Directives)
+	}
+	return ivs
+}
+
+func specifiedBy(directives []parse.Node) *string {
+	for _, n := range directives {
+		dn := n.(parse.DirectiveNode)
+		if dn.Name != "specifiedBy" {
+			continue
+		}
+		for _, a := range dn.Arguments {
+			an := a.(parse.ArgumentNode)
+			if vn, ok := an.Value.(parse.ValueNode); ok && an.Name == "url" {
+				return &vn.Value
+			}
+		}
+	}
+	return nil
+}
+
+func (b schemaBuilder) fullType(n parse.Node, possibleTypes map[string][]string) Type {
+	name, kind := kindOf(n)
+	t := Type{Kind: kind, Name: &name}
+
+	switch dn := n.(type) {
+	case parse.TypeDefNode:
+		t.Description = strPtr(dn.Description)
+		if dn.Input {
+			t.InputFields = make([]InputValue, len(dn.Fields))
+			for i, n := range dn.Fields {
+				fn := n.(parse.FieldNode)
+				t.InputFields[i] = b.inputValue(fn.Description, fn.Name, fn.Type, fn.DefaultValue, fn.Directives)
+			}
+			break
+		}
+		t.Fields = make([]Field, len(dn.Fields))
+		for i, n := range dn.Fields {
+			fn := n.(parse.FieldNode)
+			f := Field{
+				Name:        fn.Name,
+				Description: strPtr(fn.Description),
+				Args:        b.args(fn.Params),
+				Type:        b.typeRef(fn.Type),
+			}
+			f.IsDeprecated, f.DeprecationReason = deprecation(fn.Directives)
+			t.Fields[i] = f
+		}
+		t.Interfaces = b.typeRefs(dn.Interfaces)
+		if dn.Interface {
+			t.PossibleTypes = b.typeRefs(possibleTypes[name])
+		}
+	case parse.EnumDefNode:
+		t.Description = strPtr(dn.Description)
+		t.EnumValues = make([]EnumValue, len(dn.Values))
+		for i, n := range dn.Values {
+			evn := n.(parse.EnumValueNode)
+			ev := EnumValue{
+				Name:        evn.Name,
+				Description: strPtr(evn.Description),
+			}
+			ev.IsDeprecated, ev.DeprecationReason = deprecation(evn.Directives)
+			t.EnumValues[i] = ev
+		}
+	case parse.ScalarDefNode:
+		t.Description = strPtr(dn.Description)
+		t.SpecifiedByURL = specifiedBy(dn.Directives)
+	case parse.UnionDefNode:
+		t.Description = strPtr(dn.Description)
+		t.PossibleTypes = b.typeRefs(dn.Types)
+	}
+
+	return t
+}
+
+// FromDocument builds the result of the standard introspection query for a
+// parsed schema, including the built in scalars, directives and
+// introspection types. References to types the schema does not define are
+// an error, as the result could not describe them.
+func FromDocument(n parse.Node, o Options) (*Schema, error) {
+	doc, ok := n.(parse.DocumentNode)
+	if !ok {
+		return nil, fmt.Errorf("expected a document")
+	}
+
+	b := schemaBuilder{kinds: make(map[string]string), unknown: make(map[string]bool)}
+	definitions := make([]parse.Node, 0)
+	defined := make(map[string]bool)
+	if o.AWSScalars {
+		doc.Definitions = append(doc.Definitions[:len(doc.Definitions):len(doc.Definitions)], awsScalars(doc)...)
+	}
+	for _, d := range doc.Definitions {
+		if name, kind := kindOf(d); kind != "" {
+			b.kinds[name] = kind
+		}
+		if dd, ok := d.(parse.DirectiveDefNode); ok {
+			defined["@"+dd.Name] = true
+		}
+		definitions = append(definitions, d)
+	}
+	for _, d := range builtins.Definitions {
+		name, kind := kindOf(d)
+		if dd, ok := d.(parse.DirectiveDefNode); ok {
+			name = "@" + dd.Name
+		}
+		if _, ok := b.kinds[name]; ok || defined[name] {
+			continue
+		}
+		if kind != "" {
+			b.kinds[name] = kind
+		}
+		definitions = append(definitions, d)
+	}
+
+	possibleTypes := make(map[string][]string)
+	for _, d := range definitions {
+		if tdn, ok := d.(parse.TypeDefNode); ok && !tdn.Input {
+			for _, i := range tdn.Interfaces {
+				possibleTypes[i] = append(possibleTypes[i], tdn.Name)
+			}
+		}
+	}
+
+	s := Schema{
+		Types:      make([]Type, 0),
+		Directives: make([]Directive, 0),
+	}
+	for _, d := range definitions {
+		switch dn := d.(type) {
+		case parse.SchemaNode:
+			s.Description = strPtr(dn.Description)
+			for _, n := range dn.Fields {
+				fn := n.(parse.FieldNode)
+				name := &TypeName{fn.Type.(parse.TypeNode).Name}
+				switch fn.Name {
+				case "query":
+					s.QueryType = name
+				case "mutation":
+					s.MutationType = name
+				case "subscription":
+					s.SubscriptionType = name
+				}
+			}
+		case parse.DirectiveDefNode:
+			s.Directives = append(s.Directives, Directive{
+				Name:         dn.Name,
+				Description:  strPtr(dn.Description),
+				IsRepeatable: dn.Repeatable,
+				Locations:    dn.Targets,
+				Args:         b.args(dn.Params),
+			})
+		default:
+			if _, kind := kindOf(d); kind != "" {
+				s.Types = append(s.Types, b.fullType(d, possibleTypes))
+			}
+		}
+	}
+
+	if len(b.unknown) > 0 {
+		names := make([]string, 0, len(b.unknown))
+		for name := range b.unknown {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown types %v", strings.Join(names, ", "))
+	}
+
+	if s.QueryType == nil {
+		if _, ok := b.kinds["Query"]; ok {
+			s.QueryType = &TypeName{"Query"}
+		}
+	}
+	if s.MutationType == nil {
+		if _, ok := b.kinds["Mutation"]; ok {
+			s.MutationType = &TypeName{"Mutation"}
+		}
+	}
+	if s.SubscriptionType == nil {
+		if _, ok := b.kinds["Subscription"]; ok {
+			s.SubscriptionType = &TypeName{"Subscription"}
+		}
+	}
+
+	return &s, nil
+}
+
+// awsScalars returns definitions for the AppSync scalars that doc does not
+// define itself.
+func awsScalars(doc parse.DocumentNode) []parse.Node {
+	defined := make(map[string]bool)
+	for _, d := range doc.Definitions {
+		if name, kind := kindOf(d); kind != "" {
+			defined[name] = true
+		}
+	}
+	var scalars []parse.Node
+	for _, name := range AWSScalars {
+		if !defined[name] {
+			scalars = append(scalars, parse.ScalarDefNode{Name: name})
+		}
+	}
+	return scalars
+}
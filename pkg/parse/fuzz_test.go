@@ -0,0 +1,66 @@
+package parse_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+func addSeeds(f *testing.F) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.graphqls"))
+	if err != nil {
+		f.Fatalf("failed to list testdata: %v", err)
+	}
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			f.Fatalf("failed to read %v: %v", file, err)
+		}
+		f.Add(string(b))
+	}
+}
+
+func FuzzLexer(f *testing.F) {
+	addSeeds(f)
+
+	f.Fuzz(func(t *testing.T, document string) {
+		l := parse.NewLexer(document)
+
+		c := make(chan parse.Token)
+		go l.Lex(c)
+
+		var last parse.Token
+		count := 0
+		for token := range c {
+			last = token
+			count++
+		}
+
+		if count == 0 || last.TokenType != parse.EOFToken {
+			t.Fatalf("expected the last of %v tokens to be eof, got %v", count, last)
+		}
+	})
+}
+
+func FuzzParse(f *testing.F) {
+	addSeeds(f)
+
+	f.Fuzz(func(t *testing.T, document string) {
+		p := parse.New(parse.NewLexer(document))
+
+		n, err := p.Parse()
+		if err != nil {
+			if err.Error == nil {
+				t.Fatalf("got parse error without a cause at %v", err.Token)
+			}
+			return
+		}
+		if n == nil {
+			t.Fatal("got neither a node nor an error")
+		}
+
+		parse.Print(n)
+	})
+}
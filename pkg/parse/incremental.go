@@ -0,0 +1,343 @@
+package parse
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Edit replaces the text between Start and End with Text. Like Loc, lines
+// and columns are zero based and columns count runes.
+type Edit struct {
+	Start Loc
+	End   Loc
+	Text  string
+}
+
+// Document is a parsed schema that can be updated as it is edited. An
+// update re-lexes and re-parses only the top level definitions touched by
+// the edit and reuses the tokens and nodes of all other definitions.
+type Document struct {
+	text       string
+	lineStarts []int
+	tokens     []Token
+	root       DocumentNode
+	parsed     bool
+}
+
+// ParseDocument parses text into a document that can be updated.
+func ParseDocument(text string) (*Document, *Error) {
+	d := &Document{}
+	return d, d.reparse(text)
+}
+
+func (d *Document) Text() string {
+	return d.text
+}
+
+// Tokens returns the tokens of the document without whitespace and
+// comments, ending with the end of file token. They are kept even when the
+// document does not parse.
+func (d *Document) Tokens() []Token {
+	return d.tokens
+}
+
+// Root returns the parsed document. It is empty when the last parse or
+// update failed.
+func (d *Document) Root() DocumentNode {
+	return d.root
+}
+
+func (d *Document) setText(text string) {
+	d.text = text
+	d.lineStarts = append(d.lineStarts[:0], 0)
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\n':
+			d.lineStarts = append(d.lineStarts, i+1)
+		case '\r':
+			if i+1 < len(text) && text[i+1] == '\n' {
+				i++
+			}
+			d.lineStarts = append(d.lineStarts, i+1)
+		}
+	}
+}
+
+func (d *Document) reparse(text string) *Error {
+	d.setText(text)
+	d.root = DocumentNode{}
+	d.parsed = false
+
+	l := NewLexer(text)
+	d.tokens = significant(l.Tokens())
+	p := newTokenParser(d.tokens)
+	n, err := p.Parse()
+	if err != nil {
+		return err
+	}
+	d.root = n.(DocumentNode)
+	d.parsed = true
+	return nil
+}
+
+// offset converts a location into a byte offset, clamping it to the text.
+func (d *Document) offset(loc Loc) int {
+	if loc.Line < 0 {
+		return 0
+	}
+	if loc.Line >= len(d.lineStarts) {
+		return len(d.text)
+	}
+	i := d.lineStarts[loc.Line]
+	for column := 0; column < loc.Column && i < len(d.text) && d.text[i] != '\n' && d.text[i] != '\r'; column++ {
+		_, size := utf8.DecodeRuneInString(d.text[i:])
+		i += size
+	}
+	return i
+}
+
+func (d *Document) loc(offset int) Loc {
+	line := sort.SearchInts(d.lineStarts, offset+1) - 1
+	return Loc{line, utf8.RuneCountInString(d.text[d.lineStarts[line]:offset])}
+}
+
+// separated reports whether a token boundary at offset is guaranteed, so
+// that lexing the text on either side of it on its own gives the same
+// tokens as lexing all of it. A comment started earlier on the line would
+// run on past the boundary, so the boundary is only trusted when the line
+// holds no '#' before it.
+func separated(text string, offset int) bool {
+	if offset == 0 || offset == len(text) {
+		return true
+	}
+	lineStart := strings.LastIndexAny(text[:offset], "\r\n") + 1
+	if strings.ContainsRune(text[lineStart:offset], '#') {
+		return false
+	}
+	r, _ := utf8.DecodeLastRuneInString(text[:offset])
+	_, punctuator := punctuators[r]
+	return punctuator || isIgnored(r) || r == '\n' || r == '\r'
+}
+
+// significant drops whitespace and comments from tokens in place.
+func significant(tokens []Token) []Token {
+	kept := tokens[:0]
+	for _, t := range tokens {
+		if !t.TokenType.Ignored() {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// tokenIndex returns the index of the first token at or after loc.
+func (d *Document) tokenIndex(loc Loc) int {
+	return sort.Search(len(d.tokens), func(i int) bool {
+		return !beforeLoc(d.tokens[i].Loc, loc)
+	})
+}
+
+func beforeLoc(a Loc, b Loc) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+}
+
+// Update applies an edit and re-parses the definitions it touches. When
+// they no longer parse on their own, for example because a block string
+// was opened, the whole document is parsed again so the result and any
+// error are always the same as for ParseDocument.
+func (d *Document) Update(e Edit) *Error {
+	start, end := d.offset(e.Start), d.offset(e.End)
+	if end < start {
+		start, end = end, start
+	}
+	text := d.text[:start] + e.Text + d.text[end:]
+
+	defs := d.root.Definitions
+	if !d.parsed || len(defs) == 0 {
+		return d.reparse(text)
+	}
+
+	first := sort.Search(len(defs), func(i int) bool {
+		return beforeLoc(e.Start, defs[i].Loc())
+	}) - 1
+	if first < 0 {
+		first = 0
+	}
+	next := sort.Search(len(defs), func(i int) bool {
+		return beforeLoc(e.End, defs[i].Loc())
+	})
+
+	regionStart := 0
+	if first > 0 {
+		regionStart = d.offset(defs[first].Loc())
+	}
+	regionEnd := len(d.text)
+	if next < len(defs) {
+		regionEnd = d.offset(defs[next].Loc())
+	}
+	regionEnd += len(text) - len(d.text)
+
+	if !separated(text, regionStart) || !separated(text, regionEnd) {
+		return d.reparse(text)
+	}
+
+	// The tokens of the region are replaced, those before it are kept and
+	// those after it move like the definitions after it.
+	oldStart, oldEnd := d.tokenIndex(d.loc(regionStart)), len(d.tokens)
+	if next < len(defs) {
+		oldEnd = d.tokenIndex(defs[next].Loc())
+	}
+
+	d.setText(text)
+	l := newLexerAt(text[regionStart:regionEnd], d.loc(regionStart))
+	tokens := significant(l.Tokens())
+	p := newTokenParser(tokens)
+	n, err := p.Parse()
+	if err != nil {
+		return d.reparse(text)
+	}
+	nodes := n.(DocumentNode).Definitions
+
+	tail, tailTokens := defs[next:], d.tokens[oldEnd:]
+	if len(tail) > 0 {
+		// The end of file token of the region stands in for the rest.
+		tokens = tokens[:len(tokens)-1]
+		old, moved := tail[0].Loc(), d.loc(regionEnd)
+		if old != moved {
+			s := shift{old, moved.Line - old.Line, moved.Column - old.Column}
+			tail = s.nodes(tail)
+			tailTokens = s.tokens(tailTokens)
+		}
+	}
+
+	all := make([]Token, 0, oldStart+len(tokens)+len(tailTokens))
+	all = append(all, d.tokens[:oldStart]...)
+	all = append(all, tokens...)
+	d.tokens = append(all, tailTokens...)
+
+	definitions := make([]Node, 0, first+len(nodes)+len(tail))
+	definitions = append(definitions, defs[:first]...)
+	definitions = append(definitions, nodes...)
+	definitions = append(definitions, tail...)
+	if len(definitions) == 0 {
+		return d.reparse(text)
+	}
+	d.root = DocumentNode{NodeLoc{definitions[0].Loc()}, definitions}
+	return nil
+}
+
+// shift moves the locations of nodes that follow an edit. Locations on the
+// line the edit ends on also move by columns.
+type shift struct {
+	from    Loc
+	lines   int
+	columns int
+}
+
+func (s shift) loc(n NodeLoc) NodeLoc {
+	if n.NodeLoc.Line == s.from.Line {
+		n.NodeLoc.Column += s.columns
+	}
+	n.NodeLoc.Line += s.lines
+	return n
+}
+
+func (s shift) tokens(tokens []Token) []Token {
+	shifted := make([]Token, len(tokens))
+	for i, t := range tokens {
+		t.Loc = s.loc(NodeLoc{t.Loc}).NodeLoc
+		shifted[i] = t
+	}
+	return shifted
+}
+
+func (s shift) nodes(nodes []Node) []Node {
+	if nodes == nil {
+		return nil
+	}
+	shifted := make([]Node, len(nodes), len(nodes))
+	for i, n := range nodes {
+		shifted[i] = s.node(n)
+	}
+	return shifted
+}
+
+func (s shift) node(n Node) Node {
+	switch sn := n.(type) {
+	case DirectiveDefNode:
+		sn.NodeLoc = s.loc(sn.NodeLoc)
+		sn.Params = s.nodes(sn.Params)
+		return sn
+	case TypeDefNode:
+		sn.NodeLoc = s.loc(sn.NodeLoc)
+		sn.Fields = s.nodes(sn.Fields)
+		sn.Directives = s.nodes(sn.Directives)
+		return sn
+	case EnumDefNode:
+		sn.NodeLoc = s.loc(sn.NodeLoc)
+		sn.Values = s.nodes(sn.Values)
+		sn.Directives = s.nodes(sn.Directives)
+		return sn
+	case EnumValueNode:
+		sn.NodeLoc = s.loc(sn.NodeLoc)
+		sn.Directives = s.nodes(sn.Directives)
+		return sn
+	case ScalarDefNode:
+		sn.NodeLoc = s.loc(sn.NodeLoc)
+		sn.Directives = s.nodes(sn.Directives)
+		return sn
+	case UnionDefNode:
+		sn.NodeLoc = s.loc(sn.NodeLoc)
+		sn.Directives = s.nodes(sn.Directives)
+		return sn
+	case SchemaNode:
+		sn.NodeLoc = s.loc(sn.NodeLoc)
+		sn.Fields = s.nodes(sn.Fields)
+		sn.Directives = s.nodes(sn.Directives)
+		return sn
+	case FieldNode:
+		sn.NodeLoc = s.loc(sn.NodeLoc)
+		sn.Type = s.node(sn.Type)
+		sn.Params = s.nodes(sn.Params)
+		sn.DefaultValue = s.node(sn.DefaultValue)
+		sn.Directives = s.nodes(sn.Directives)
+		return sn
+	case TypeNode:
+		sn.NodeLoc = s.loc(sn.NodeLoc)
+		return sn
+	case ParamNode:
+		sn.NodeLoc = s.loc(sn.NodeLoc)
+		sn.Type = s.node(sn.Type)
+		sn.DefaultValue = s.node(sn.DefaultValue)
+		sn.Directives = s.nodes(sn.Directives)
+		return sn
+	case DirectiveNode:
+		sn.NodeLoc = s.loc(sn.NodeLoc)
+		sn.Arguments = s.nodes(sn.Arguments)
+		return sn
+	case ArgumentNode:
+		sn.NodeLoc = s.loc(sn.NodeLoc)
+		sn.Value = s.node(sn.Value)
+		return sn
+	case ValueNode:
+		sn.NodeLoc = s.loc(sn.NodeLoc)
+		return sn
+	case ListValueNode:
+		sn.NodeLoc = s.loc(sn.NodeLoc)
+		sn.Values = s.nodes(sn.Values)
+		return sn
+	case ObjectValueNode:
+		sn.NodeLoc = s.loc(sn.NodeLoc)
+		sn.Fields = s.nodes(sn.Fields)
+		return sn
+	case TokenNode:
+		sn.NodeLoc = s.loc(sn.NodeLoc)
+		return sn
+	case MultiNode:
+		sn.NodeLoc = s.loc(sn.NodeLoc)
+		sn.Nodes = s.nodes(sn.Nodes)
+		return sn
+	}
+	return n
+}
@@ -0,0 +1,230 @@
+package parse_test
+
+import (
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+func checkUpdate(t *testing.T, d *parse.Document, e parse.Edit) {
+	before := d.Text()
+	err := d.Update(e)
+
+	expected, expectedErr := parse.ParseDocument(d.Text())
+	if (err == nil) != (expectedErr == nil) {
+		t.Fatalf("applying %+v to %q: expected error %v got %v", e, before, expectedErr, err)
+	}
+	if diff := cmp.Diff(expected.Tokens(), d.Tokens()); diff != "" {
+		t.Fatalf("applying %+v to %q: token mismatch (expected, got) %v", e, before, diff)
+	}
+	if err != nil {
+		if err.Error.Error() != expectedErr.Error.Error() || err.Token != expectedErr.Token {
+			t.Fatalf("applying %+v to %q: expected error %v (%v) got %v (%v)", e, before, expectedErr.Error, expectedErr.Token, err.Error, err.Token)
+		}
+		return
+	}
+	if diff := cmp.Diff(expected.Root(), d.Root()); diff != "" {
+		t.Fatalf("applying %+v to %q: mismatch (expected, got) %v", e, before, diff)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	schema := "scalar A\n\ntype B {\n  c: A\n}\n\nenum D { E F }\n"
+
+	tests := map[string][]parse.Edit{
+		"rename field": {
+			{Start: parse.Loc{Line: 3, Column: 3}, End: parse.Loc{Line: 3, Column: 3}, Text: "x"},
+		},
+		"add line": {
+			{Start: parse.Loc{Line: 3, Column: 7}, End: parse.Loc{Line: 3, Column: 7}, Text: "\n  d: Int"},
+		},
+		"remove line": {
+			{Start: parse.Loc{Line: 1, Column: 0}, End: parse.Loc{Line: 2, Column: 0}},
+		},
+		"add definition": {
+			{Start: parse.Loc{Line: 5, Column: 0}, End: parse.Loc{Line: 5, Column: 0}, Text: "scalar G\n"},
+		},
+		"remove definition": {
+			{Start: parse.Loc{Line: 2, Column: 0}, End: parse.Loc{Line: 6, Column: 0}},
+		},
+		"break and fix": {
+			{Start: parse.Loc{Line: 3, Column: 3}, End: parse.Loc{Line: 3, Column: 4}},
+			{Start: parse.Loc{Line: 3, Column: 2}, End: parse.Loc{Line: 3, Column: 2}, Text: "c"},
+		},
+		"open block string": {
+			{Start: parse.Loc{Line: 2, Column: 0}, End: parse.Loc{Line: 2, Column: 0}, Text: `"""`},
+			{Start: parse.Loc{Line: 6, Column: 0}, End: parse.Loc{Line: 6, Column: 0}, Text: `"""`},
+		},
+		"extend union": {
+			{Start: parse.Loc{Line: 0, Column: 0}, End: parse.Loc{Line: 0, Column: 8}, Text: "union U = B"},
+			{Start: parse.Loc{Line: 2, Column: 0}, End: parse.Loc{Line: 2, Column: 0}, Text: "| X "},
+		},
+		"join tokens": {
+			{Start: parse.Loc{Line: 0, Column: 8}, End: parse.Loc{Line: 2, Column: 0}},
+		},
+		"same line definitions": {
+			{Start: parse.Loc{Line: 0, Column: 8}, End: parse.Loc{Line: 2, Column: 0}, Text: " "},
+			{Start: parse.Loc{Line: 0, Column: 7}, End: parse.Loc{Line: 0, Column: 8}, Text: "AAA"},
+		},
+		"crlf": {
+			{Start: parse.Loc{Line: 0, Column: 8}, End: parse.Loc{Line: 1, Column: 0}, Text: "\r\n"},
+			{Start: parse.Loc{Line: 3, Column: 3}, End: parse.Loc{Line: 3, Column: 3}, Text: "x"},
+		},
+		"clear": {
+			{Start: parse.Loc{Line: 0, Column: 0}, End: parse.Loc{Line: 7, Column: 0}},
+			{Start: parse.Loc{Line: 0, Column: 0}, End: parse.Loc{Line: 0, Column: 0}, Text: "scalar A"},
+		},
+	}
+
+	for name, edits := range tests {
+		t.Run(name, func(t *testing.T) {
+			d, err := parse.ParseDocument(schema)
+			if err != nil {
+				t.Fatalf("failed to parse %v", err)
+			}
+			for _, e := range edits {
+				checkUpdate(t, d, e)
+			}
+		})
+	}
+}
+
+func TestUpdateSameLine(t *testing.T) {
+	schema := "type A { a: Int } type B { b: Int }\n"
+
+	tests := map[string][]parse.Edit{
+		"comment out next definition": {
+			{Start: parse.Loc{Line: 0, Column: 17}, End: parse.Loc{Line: 0, Column: 17}, Text: "#"},
+			{Start: parse.Loc{Line: 0, Column: 17}, End: parse.Loc{Line: 0, Column: 18}},
+		},
+		"comment out rest of definition": {
+			{Start: parse.Loc{Line: 0, Column: 7}, End: parse.Loc{Line: 0, Column: 7}, Text: "#"},
+		},
+		"comment in string": {
+			{Start: parse.Loc{Line: 0, Column: 0}, End: parse.Loc{Line: 0, Column: 0}, Text: `"#" `},
+			{Start: parse.Loc{Line: 0, Column: 29}, End: parse.Loc{Line: 0, Column: 29}, Text: "c: Int "},
+		},
+	}
+
+	for name, edits := range tests {
+		t.Run(name, func(t *testing.T) {
+			d, err := parse.ParseDocument(schema)
+			if err != nil {
+				t.Fatalf("failed to parse %v", err)
+			}
+			for _, e := range edits {
+				checkUpdate(t, d, e)
+			}
+		})
+	}
+}
+
+func randomLoc(r *rand.Rand, text string) parse.Loc {
+	lines := strings.Split(text, "\n")
+	line := r.Intn(len(lines))
+	return parse.Loc{Line: line, Column: r.Intn(len([]rune(lines[line])) + 1)}
+}
+
+func TestUpdateRandom(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.graphqls"))
+	if err != nil {
+		t.Fatalf("failed to list testdata %v", err)
+	}
+	inserts := []string{"", "a", " ", "\n", "}", "{", "@", "|", "\"", `"""`, "#", "type X { y: Int }\n", "scalar Z "}
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			d, _ := parse.ParseDocument(parse.TestGetDoc(t, filepath.Base(file)))
+			for i := 0; i < 200; i++ {
+				start := randomLoc(r, d.Text())
+				end := start
+				if r.Intn(2) == 0 {
+					end = randomLoc(r, d.Text())
+					if end.Line < start.Line || (end.Line == start.Line && end.Column < start.Column) {
+						start, end = end, start
+					}
+					if end.Line > start.Line+1 {
+						end = start
+					}
+				}
+				checkUpdate(t, d, parse.Edit{Start: start, End: end, Text: inserts[r.Intn(len(inserts))]})
+			}
+		})
+	}
+}
+
+func largeSchema(types int) string {
+	var b strings.Builder
+	for i := 0; i < types; i++ {
+		fmt.Fprintf(&b, "\"Type number %v\"\ntype Type%v implements Node @key(fields: \"id\") {\n", i, i)
+		b.WriteString("  id: ID!\n")
+		for j := 0; j < 10; j++ {
+			fmt.Fprintf(&b, "  field%v(first: Int = 10, after: String): [Type%v!]! @resolve\n", j, (i+j)%types)
+		}
+		b.WriteString("}\n\n")
+	}
+	return b.String()
+}
+
+func BenchmarkParseLargeSchema(b *testing.B) {
+	schema := largeSchema(1000)
+	b.SetBytes(int64(len(schema)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := parse.ParseDocument(schema); err != nil {
+			b.Fatalf("failed to parse %v", err)
+		}
+	}
+}
+
+// BenchmarkUpdateKeystroke types and deletes a character in a field name in
+// the middle of the schema.
+func BenchmarkUpdateKeystroke(b *testing.B) {
+	d, err := parse.ParseDocument(largeSchema(1000))
+	if err != nil {
+		b.Fatalf("failed to parse %v", err)
+	}
+	at := parse.Loc{Line: 500*15 + 3, Column: 4}
+	after := parse.Loc{Line: at.Line, Column: at.Column + 1}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		e := parse.Edit{Start: at, End: at, Text: "x"}
+		if i%2 == 1 {
+			e = parse.Edit{Start: at, End: after}
+		}
+		if err := d.Update(e); err != nil {
+			b.Fatalf("failed to update %v", err)
+		}
+	}
+}
+
+// BenchmarkUpdateNewline inserts and removes a line, which moves every
+// definition after it.
+func BenchmarkUpdateNewline(b *testing.B) {
+	d, err := parse.ParseDocument(largeSchema(1000))
+	if err != nil {
+		b.Fatalf("failed to parse %v", err)
+	}
+	at := parse.Loc{Line: 500*15 + 3, Column: 0}
+	after := parse.Loc{Line: at.Line + 1, Column: 0}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		e := parse.Edit{Start: at, End: at, Text: "\n"}
+		if i%2 == 1 {
+			e = parse.Edit{Start: at, End: after}
+		}
+		if err := d.Update(e); err != nil {
+			b.Fatalf("failed to update %v", err)
+		}
+	}
+}
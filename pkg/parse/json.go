@@ -0,0 +1,432 @@
+package parse
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// jsonLoc is a position in the JSON form of a node. Unlike Loc, lines and
+// columns start at 1.
+type jsonLoc struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// jsonNode is the JSON form shared by all nodes. Lists are pointers so
+// that a missing list and an empty one survive a round trip.
+type jsonNode struct {
+	Kind            string          `json:"kind"`
+	Loc             jsonLoc         `json:"loc"`
+	Description     string          `json:"description,omitempty"`
+	Name            string          `json:"name,omitempty"`
+	ValueKind       string          `json:"valueKind,omitempty"`
+	TokenType       string          `json:"tokenType,omitempty"`
+	Value           json.RawMessage `json:"value,omitempty"`
+	Required        bool            `json:"required,omitempty"`
+	Multiple        bool            `json:"multiple,omitempty"`
+	NonNullElements bool            `json:"nonNullElements,omitempty"`
+	Input           bool            `json:"input,omitempty"`
+	Interface       bool            `json:"interface,omitempty"`
+	Repeatable      bool            `json:"repeatable,omitempty"`
+	Interfaces      *[]string       `json:"interfaces,omitempty"`
+	Types           *[]string       `json:"types,omitempty"`
+	Targets         *[]string       `json:"targets,omitempty"`
+	Type            *jsonNode       `json:"type,omitempty"`
+	DefaultValue    *jsonNode       `json:"defaultValue,omitempty"`
+	Definitions     *[]*jsonNode    `json:"definitions,omitempty"`
+	Params          *[]*jsonNode    `json:"params,omitempty"`
+	Fields          *[]*jsonNode    `json:"fields,omitempty"`
+	Values          *[]*jsonNode    `json:"values,omitempty"`
+	Arguments       *[]*jsonNode    `json:"arguments,omitempty"`
+	Directives      *[]*jsonNode    `json:"directives,omitempty"`
+	Nodes           *[]*jsonNode    `json:"nodes,omitempty"`
+}
+
+var valueKinds = map[ValueKind]string{
+	IntValue:     "Int",
+	FloatValue:   "Float",
+	StringValue:  "String",
+	BooleanValue: "Boolean",
+	NullValue:    "Null",
+	EnumValue:    "Enum",
+}
+
+func (k ValueKind) String() string {
+	if s, ok := valueKinds[k]; ok {
+		return s
+	}
+	return "unknown"
+}
+
+func strs(s []string) *[]string {
+	if s == nil {
+		return nil
+	}
+	return &s
+}
+
+func unstrs(s *[]string) []string {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+func raw(v interface{}) json.RawMessage {
+	b, _ := json.Marshal(v)
+	return b
+}
+
+func toJSONList(nodes []Node) (*[]*jsonNode, error) {
+	if nodes == nil {
+		return nil, nil
+	}
+	list := make([]*jsonNode, len(nodes))
+	for i, n := range nodes {
+		jn, err := toJSON(n)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = jn
+	}
+	return &list, nil
+}
+
+// toJSON converts a node into its JSON form. The first error from a child
+// list is kept in err so the cases below stay flat.
+func toJSON(n Node) (*jsonNode, error) {
+	if n == nil {
+		return nil, nil
+	}
+
+	var err error
+	list := func(nodes []Node) *[]*jsonNode {
+		l, lerr := toJSONList(nodes)
+		if err == nil {
+			err = lerr
+		}
+		return l
+	}
+	single := func(n Node) *jsonNode {
+		jn, jerr := toJSON(n)
+		if err == nil {
+			err = jerr
+		}
+		return jn
+	}
+
+	jn := &jsonNode{Loc: jsonLoc{n.Loc().Line + 1, n.Loc().Column + 1}}
+	switch tn := n.(type) {
+	case DocumentNode:
+		jn.Kind = "Document"
+		jn.Definitions = list(tn.Definitions)
+	case MultiNode:
+		jn.Kind = "Multi"
+		jn.Nodes = list(tn.Nodes)
+	case DirectiveDefNode:
+		jn.Kind = "DirectiveDef"
+		jn.Description = tn.Description
+		jn.Name = tn.Name
+		jn.Params = list(tn.Params)
+		jn.Repeatable = tn.Repeatable
+		jn.Targets = strs(tn.Targets)
+	case TypeDefNode:
+		jn.Kind = "TypeDef"
+		jn.Description = tn.Description
+		jn.Name = tn.Name
+		jn.Fields = list(tn.Fields)
+		jn.Input = tn.Input
+		jn.Interface = tn.Interface
+		jn.Interfaces = strs(tn.Interfaces)
+		jn.Directives = list(tn.Directives)
+	case EnumDefNode:
+		jn.Kind = "EnumDef"
+		jn.Description = tn.Description
+		jn.Name = tn.Name
+		jn.Values = list(tn.Values)
+		jn.Directives = list(tn.Directives)
+	case EnumValueNode:
+		jn.Kind = "EnumValue"
+		jn.Description = tn.Description
+		jn.Name = tn.Name
+		jn.Directives = list(tn.Directives)
+	case ScalarDefNode:
+		jn.Kind = "ScalarDef"
+		jn.Description = tn.Description
+		jn.Name = tn.Name
+		jn.Directives = list(tn.Directives)
+	case UnionDefNode:
+		jn.Kind = "UnionDef"
+		jn.Description = tn.Description
+		jn.Name = tn.Name
+		jn.Types = strs(tn.Types)
+		jn.Directives = list(tn.Directives)
+	case SchemaNode:
+		jn.Kind = "Schema"
+		jn.Description = tn.Description
+		jn.Fields = list(tn.Fields)
+		jn.Directives = list(tn.Directives)
+	case FieldNode:
+		jn.Kind = "Field"
+		jn.Description = tn.Description
+		jn.Name = tn.Name
+		jn.Type = single(tn.Type)
+		jn.Params = list(tn.Params)
+		jn.DefaultValue = single(tn.DefaultValue)
+		jn.Directives = list(tn.Directives)
+	case TypeNode:
+		jn.Kind = "Type"
+		jn.Name = tn.Name
+		jn.Required = tn.Required
+		jn.Multiple = tn.Multiple
+		jn.NonNullElements = tn.NonNullElements
+	case ParamNode:
+		jn.Kind = "Param"
+		jn.Description = tn.Description
+		jn.Name = tn.Name
+		jn.Type = single(tn.Type)
+		jn.DefaultValue = single(tn.DefaultValue)
+		jn.Directives = list(tn.Directives)
+	case DirectiveNode:
+		jn.Kind = "Directive"
+		jn.Name = tn.Name
+		jn.Arguments = list(tn.Arguments)
+	case ArgumentNode:
+		jn.Kind = "Argument"
+		jn.Name = tn.Name
+		if v := single(tn.Value); v != nil {
+			jn.Value = raw(v)
+		}
+	case ValueNode:
+		jn.Kind = "Value"
+		jn.ValueKind = tn.Kind.String()
+		jn.Value = raw(tn.Value)
+	case ListValueNode:
+		jn.Kind = "ListValue"
+		jn.Values = list(tn.Values)
+	case ObjectValueNode:
+		jn.Kind = "ObjectValue"
+		jn.Fields = list(tn.Fields)
+	case TokenNode:
+		jn.Kind = "Token"
+		jn.TokenType = tn.TokenType.String()
+		jn.Value = raw(tn.Value)
+	default:
+		return nil, fmt.Errorf("cannot encode node of type %T", n)
+	}
+	return jn, err
+}
+
+func fromJSONList(list *[]*jsonNode) ([]Node, error) {
+	if list == nil {
+		return nil, nil
+	}
+	nodes := make([]Node, len(*list))
+	for i, jn := range *list {
+		n, err := fromJSON(jn)
+		if err != nil {
+			return nil, err
+		}
+		nodes[i] = n
+	}
+	return nodes, nil
+}
+
+func tokenType(s string) (TokenType, bool) {
+	for tt := ErrorToken; tt <= CommentToken; tt++ {
+		if tt.String() == s {
+			return tt, true
+		}
+	}
+	return ErrorToken, false
+}
+
+func valueKind(s string) (ValueKind, bool) {
+	for k, name := range valueKinds {
+		if name == s {
+			return k, true
+		}
+	}
+	return IntValue, false
+}
+
+// fromJSON converts the JSON form back into a node, following the same
+// pattern as toJSON for child errors.
+func fromJSON(jn *jsonNode) (Node, error) {
+	if jn == nil {
+		return nil, nil
+	}
+
+	var err error
+	list := func(l *[]*jsonNode) []Node {
+		nodes, lerr := fromJSONList(l)
+		if err == nil {
+			err = lerr
+		}
+		return nodes
+	}
+	single := func(jn *jsonNode) Node {
+		n, jerr := fromJSON(jn)
+		if err == nil {
+			err = jerr
+		}
+		return n
+	}
+	str := func() string {
+		var s string
+		if len(jn.Value) > 0 {
+			if serr := json.Unmarshal(jn.Value, &s); serr != nil && err == nil {
+				err = fmt.Errorf("%v value must be a string: %v", jn.Kind, serr)
+			}
+		}
+		return s
+	}
+
+	nodeLoc := NodeLoc{Loc{jn.Loc.Line - 1, jn.Loc.Column - 1}}
+	var n Node
+	switch jn.Kind {
+	case "Document":
+		n = DocumentNode{nodeLoc, list(jn.Definitions)}
+	case "Multi":
+		n = MultiNode{nodeLoc, list(jn.Nodes)}
+	case "DirectiveDef":
+		n = DirectiveDefNode{nodeLoc, jn.Description, jn.Name, list(jn.Params), jn.Repeatable, unstrs(jn.Targets)}
+	case "TypeDef":
+		n = TypeDefNode{nodeLoc, jn.Description, jn.Name, list(jn.Fields), jn.Input, jn.Interface, unstrs(jn.Interfaces), list(jn.Directives)}
+	case "EnumDef":
+		n = EnumDefNode{nodeLoc, jn.Description, jn.Name, list(jn.Values), list(jn.Directives)}
+	case "EnumValue":
+		n = EnumValueNode{nodeLoc, jn.Description, jn.Name, list(jn.Directives)}
+	case "ScalarDef":
+		n = ScalarDefNode{nodeLoc, jn.Description, jn.Name, list(jn.Directives)}
+	case "UnionDef":
+		n = UnionDefNode{nodeLoc, jn.Description, jn.Name, unstrs(jn.Types), list(jn.Directives)}
+	case "Schema":
+		n = SchemaNode{nodeLoc, jn.Description, list(jn.Fields), list(jn.Directives)}
+	case "Field":
+		n = FieldNode{nodeLoc, jn.Description, jn.Name, single(jn.Type), list(jn.Params), single(jn.DefaultValue), list(jn.Directives)}
+	case "Type":
+		n = TypeNode{nodeLoc, LeafNode{}, jn.Name, jn.Required, jn.Multiple, jn.NonNullElements}
+	case "Param":
+		n = ParamNode{nodeLoc, jn.Description, jn.Name, single(jn.Type), single(jn.DefaultValue), list(jn.Directives)}
+	case "Directive":
+		n = DirectiveNode{nodeLoc, jn.Name, list(jn.Arguments)}
+	case "Argument":
+		var value *jsonNode
+		if len(jn.Value) > 0 {
+			if verr := json.Unmarshal(jn.Value, &value); verr != nil {
+				return nil, fmt.Errorf("Argument value must be a node: %v", verr)
+			}
+		}
+		n = ArgumentNode{nodeLoc, jn.Name, single(value)}
+	case "Value":
+		kind, ok := valueKind(jn.ValueKind)
+		if !ok {
+			return nil, fmt.Errorf("unknown value kind %q", jn.ValueKind)
+		}
+		n = ValueNode{nodeLoc, LeafNode{}, kind, str()}
+	case "ListValue":
+		n = ListValueNode{nodeLoc, list(jn.Values)}
+	case "ObjectValue":
+		n = ObjectValueNode{nodeLoc, list(jn.Fields)}
+	case "Token":
+		tt, ok := tokenType(jn.TokenType)
+		if !ok {
+			return nil, fmt.Errorf("unknown token type %q", jn.TokenType)
+		}
+		n = TokenNode{nodeLoc, LeafNode{}, tt, str()}
+	default:
+		return nil, fmt.Errorf("unknown node kind %q", jn.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// MarshalNode encodes a node and all of its children as JSON. Every node
+// carries a kind naming its type and a 1 based loc.
+func MarshalNode(n Node) ([]byte, error) {
+	jn, err := toJSON(n)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jn)
+}
+
+// MarshalNodeIndent is like MarshalNode but indents the output like
+// json.MarshalIndent.
+func MarshalNodeIndent(n Node, prefix string, indent string) ([]byte, error) {
+	jn, err := toJSON(n)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(jn, prefix, indent)
+}
+
+// UnmarshalNode decodes a node written by MarshalNode.
+func UnmarshalNode(data []byte) (Node, error) {
+	var jn *jsonNode
+	if err := json.Unmarshal(data, &jn); err != nil {
+		return nil, fmt.Errorf("failed to decode node: %v", err)
+	}
+	if jn == nil {
+		return nil, nil
+	}
+	return fromJSON(jn)
+}
+
+// unmarshalInto decodes data into target, a pointer to a node type, and
+// fails when data holds a node of another kind.
+func unmarshalInto(data []byte, target interface{}) error {
+	n, err := UnmarshalNode(data)
+	if err != nil {
+		return err
+	}
+	tv := reflect.ValueOf(target).Elem()
+	nv := reflect.ValueOf(n)
+	if !nv.IsValid() || nv.Type() != tv.Type() {
+		return fmt.Errorf("cannot decode %T into %v", n, tv.Type())
+	}
+	tv.Set(nv)
+	return nil
+}
+
+func (n DocumentNode) MarshalJSON() ([]byte, error)     { return MarshalNode(n) }
+func (n MultiNode) MarshalJSON() ([]byte, error)        { return MarshalNode(n) }
+func (n DirectiveDefNode) MarshalJSON() ([]byte, error) { return MarshalNode(n) }
+func (n TypeDefNode) MarshalJSON() ([]byte, error)      { return MarshalNode(n) }
+func (n EnumDefNode) MarshalJSON() ([]byte, error)      { return MarshalNode(n) }
+func (n EnumValueNode) MarshalJSON() ([]byte, error)    { return MarshalNode(n) }
+func (n ScalarDefNode) MarshalJSON() ([]byte, error)    { return MarshalNode(n) }
+func (n UnionDefNode) MarshalJSON() ([]byte, error)     { return MarshalNode(n) }
+func (n SchemaNode) MarshalJSON() ([]byte, error)       { return MarshalNode(n) }
+func (n FieldNode) MarshalJSON() ([]byte, error)        { return MarshalNode(n) }
+func (n TypeNode) MarshalJSON() ([]byte, error)         { return MarshalNode(n) }
+func (n ParamNode) MarshalJSON() ([]byte, error)        { return MarshalNode(n) }
+func (n DirectiveNode) MarshalJSON() ([]byte, error)    { return MarshalNode(n) }
+func (n ArgumentNode) MarshalJSON() ([]byte, error)     { return MarshalNode(n) }
+func (n ValueNode) MarshalJSON() ([]byte, error)        { return MarshalNode(n) }
+func (n ListValueNode) MarshalJSON() ([]byte, error)    { return MarshalNode(n) }
+func (n ObjectValueNode) MarshalJSON() ([]byte, error)  { return MarshalNode(n) }
+func (n TokenNode) MarshalJSON() ([]byte, error)        { return MarshalNode(n) }
+
+func (n *DocumentNode) UnmarshalJSON(data []byte) error     { return unmarshalInto(data, n) }
+func (n *MultiNode) UnmarshalJSON(data []byte) error        { return unmarshalInto(data, n) }
+func (n *DirectiveDefNode) UnmarshalJSON(data []byte) error { return unmarshalInto(data, n) }
+func (n *TypeDefNode) UnmarshalJSON(data []byte) error      { return unmarshalInto(data, n) }
+func (n *EnumDefNode) UnmarshalJSON(data []byte) error      { return unmarshalInto(data, n) }
+func (n *EnumValueNode) UnmarshalJSON(data []byte) error    { return unmarshalInto(data, n) }
+func (n *ScalarDefNode) UnmarshalJSON(data []byte) error    { return unmarshalInto(data, n) }
+func (n *UnionDefNode) UnmarshalJSON(data []byte) error     { return unmarshalInto(data, n) }
+func (n *SchemaNode) UnmarshalJSON(data []byte) error       { return unmarshalInto(data, n) }
+func (n *FieldNode) UnmarshalJSON(data []byte) error        { return unmarshalInto(data, n) }
+func (n *TypeNode) UnmarshalJSON(data []byte) error         { return unmarshalInto(data, n) }
+func (n *ParamNode) UnmarshalJSON(data []byte) error        { return unmarshalInto(data, n) }
+func (n *DirectiveNode) UnmarshalJSON(data []byte) error    { return unmarshalInto(data, n) }
+func (n *ArgumentNode) UnmarshalJSON(data []byte) error     { return unmarshalInto(data, n) }
+func (n *ValueNode) UnmarshalJSON(data []byte) error        { return unmarshalInto(data, n) }
+func (n *ListValueNode) UnmarshalJSON(data []byte) error    { return unmarshalInto(data, n) }
+func (n *ObjectValueNode) UnmarshalJSON(data []byte) error  { return unmarshalInto(data, n) }
+func (n *TokenNode) UnmarshalJSON(data []byte) error        { return unmarshalInto(data, n) }
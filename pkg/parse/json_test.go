@@ -0,0 +1,93 @@
+package parse_test
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+var update = flag.Bool("update", false, "rewrite the JSON snapshots in testdata")
+
+func TestJSONSnapshots(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.graphqls"))
+	if err != nil {
+		t.Fatalf("failed to list testdata %v", err)
+	}
+
+	for _, file := range files {
+		name := filepath.Base(file)
+		t.Run(name, func(t *testing.T) {
+			ast := parse.TestParse(t, parse.TestGetDoc(t, name))
+
+			got, err := parse.MarshalNodeIndent(ast, "", "  ")
+			if err != nil {
+				t.Fatalf("failed to encode %v", err)
+			}
+			got = append(got, '\n')
+
+			snapshot := filepath.Join("testdata", strings.TrimSuffix(name, ".graphqls")+".json")
+			if *update {
+				if err := ioutil.WriteFile(snapshot, got, 0644); err != nil {
+					t.Fatalf("failed to write snapshot %v", err)
+				}
+			}
+			expected, err := ioutil.ReadFile(snapshot)
+			if err != nil {
+				t.Fatalf("failed to read snapshot %v (run go test -update to create it)", err)
+			}
+			if diff := cmp.Diff(string(expected), string(got)); diff != "" {
+				t.Fatalf("mismatch (expected, got) %v", diff)
+			}
+
+			decoded, err := parse.UnmarshalNode(expected)
+			if err != nil {
+				t.Fatalf("failed to decode %v", err)
+			}
+			if diff := cmp.Diff(ast, decoded); diff != "" {
+				t.Fatalf("round trip mismatch (expected, got) %v", diff)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	var fn parse.FieldNode
+	data := `{"kind":"Field","loc":{"line":2,"column":3},"name":"a","type":{"kind":"Type","loc":{"line":2,"column":6},"name":"Int","required":true}}`
+	if err := json.Unmarshal([]byte(data), &fn); err != nil {
+		t.Fatalf("failed to decode %v", err)
+	}
+	expected := parse.FieldNode{
+		NodeLoc: parse.NodeLoc{NodeLoc: parse.Loc{Line: 1, Column: 2}},
+		Name:    "a",
+		Type: parse.TypeNode{
+			NodeLoc:  parse.NodeLoc{NodeLoc: parse.Loc{Line: 1, Column: 5}},
+			Name:     "Int",
+			Required: true,
+		},
+	}
+	if diff := cmp.Diff(expected, fn); diff != "" {
+		t.Fatalf("mismatch (expected, got) %v", diff)
+	}
+
+	errors := map[string]string{
+		"wrong kind":         `{"kind":"Type","loc":{"line":1,"column":1}}`,
+		"unknown kind":       `{"kind":"Fragment","loc":{"line":1,"column":1}}`,
+		"unknown value kind": `{"kind":"Field","type":{"kind":"Value","valueKind":"Date"}}`,
+		"not an object":      `[]`,
+	}
+	for name, data := range errors {
+		t.Run(name, func(t *testing.T) {
+			var fn parse.FieldNode
+			if err := json.Unmarshal([]byte(data), &fn); err == nil {
+				t.Fatalf("expected an error decoding %v", data)
+			}
+		})
+	}
+}
@@ -2,24 +2,60 @@ package parse
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
-	"unicode"
 )
 
 type Lexer struct {
 	lines  []string
 	dSlice []rune
 	loc    Loc
+	base   Loc
+}
+
+// splitLines splits a document on any of the GraphQL line terminators:
+// "\r\n", "\r" and "\n".
+func splitLines(document string) []string {
+	lines := make([]string, 0)
+	start := 0
+	for i := 0; i < len(document); i++ {
+		switch document[i] {
+		case '\n':
+			lines = append(lines, document[start:i])
+			start = i + 1
+		case '\r':
+			lines = append(lines, document[start:i])
+			if i+1 < len(document) && document[i+1] == '\n' {
+				i++
+			}
+			start = i + 1
+		}
+	}
+	return append(lines, document[start:])
 }
 
 func NewLexer(document string) Lexer {
-	lines := strings.Split(document, "\n")
+	lines := splitLines(document)
 	dSlice := []rune(lines[0])
-	return Lexer{lines: lines, dSlice: dSlice}
+	l := Lexer{lines: lines, dSlice: dSlice}
+	l.checkNextLine()
+	return l
+}
+
+// newLexerAt returns a lexer for a fragment of a larger document that
+// starts at base, so its tokens carry locations within that document.
+func newLexerAt(fragment string, base Loc) Lexer {
+	l := NewLexer(fragment)
+	l.base = base
+	return l
 }
 
 func (l Lexer) newToken(t TokenType, v string, loc Loc) Token {
-	return Token{t, l.loc, v}
+	if loc.Line == 0 {
+		loc.Column += l.base.Column
+	}
+	loc.Line += l.base.Line
+	return Token{t, loc, v}
 }
 
 func (l Lexer) isLastLine() bool {
@@ -64,60 +100,292 @@ func (l Lexer) isDone() bool {
 	return l.isLastLine() && l.isEndOfLine()
 }
 
+func isNameStart(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_'
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
 func isText(r rune) bool {
-	return unicode.IsLetter(r) || r == '_'
+	return isNameStart(r) || isDigit(r)
+}
+
+// isIgnored reports whether r is insignificant outside of strings. Line
+// terminators never reach the lexer loop since the document is split on
+// them.
+func isIgnored(r rune) bool {
+	return r == ' ' || r == '\t' || r == ',' || r == '\uFEFF'
+}
+
+func (l Lexer) peek(s string) bool {
+	rs := []rune(s)
+	if l.loc.Column+len(rs) > len(l.dSlice) {
+		return false
+	}
+	for i, r := range rs {
+		if l.dSlice[l.loc.Column+i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *Lexer) digits() int {
+	count := 0
+	for !l.isEndOfLine() && isDigit(l.currentRune()) {
+		l.loc.Column++
+		count++
+	}
+	return count
+}
+
+func (l *Lexer) lexNumber() Token {
+	s := l.loc
+	start := l.loc.Column
+	tt := IntToken
+	valid := true
+
+	if l.currentRune() == '-' {
+		l.loc.Column++
+	}
+	if l.peek("0") && l.loc.Column+1 < len(l.dSlice) && isDigit(l.dSlice[l.loc.Column+1]) {
+		valid = false
+	}
+	if l.digits() == 0 {
+		valid = false
+	}
+	if !l.isEndOfLine() && l.currentRune() == '.' {
+		tt = FloatToken
+		l.loc.Column++
+		if l.digits() == 0 {
+			valid = false
+		}
+	}
+	if !l.isEndOfLine() && (l.currentRune() == 'e' || l.currentRune() == 'E') {
+		tt = FloatToken
+		l.loc.Column++
+		if !l.isEndOfLine() && (l.currentRune() == '+' || l.currentRune() == '-') {
+			l.loc.Column++
+		}
+		if l.digits() == 0 {
+			valid = false
+		}
+	}
+	for !l.isEndOfLine() && (isText(l.currentRune()) || l.currentRune() == '.') {
+		valid = false
+		l.loc.Column++
+	}
+
+	v := string(l.dSlice[start:l.loc.Column])
+	l.checkNextLine()
+
+	if !valid {
+		return l.newToken(ErrorToken, fmt.Sprintf("invalid number %v", v), s)
+	}
+	return l.newToken(tt, v, s)
 }
 
+var escapes = map[rune]rune{
+	'"':  '"',
+	'\\': '\\',
+	'/':  '/',
+	'b':  '\b',
+	'f':  '\f',
+	'n':  '\n',
+	'r':  '\r',
+	't':  '\t',
+}
+
+func (l *Lexer) lexString() Token {
+	if l.peek(`"""`) {
+		return l.lexBlockString()
+	}
+
+	s := l.loc
+	l.loc.Column++
+
+	var b strings.Builder
+	for !l.isEndOfLine() {
+		r := l.currentRune()
+		l.loc.Column++
+
+		switch {
+		case r == '"':
+			l.checkNextLine()
+			return l.newToken(StringToken, b.String(), s)
+		case r == '\\' && l.isEndOfLine():
+		case r == '\\' && l.currentRune() == 'u':
+			l.loc.Column++
+			if l.loc.Column+4 > len(l.dSlice) {
+				l.loc.Column = len(l.dSlice)
+				break
+			}
+			hex := string(l.dSlice[l.loc.Column : l.loc.Column+4])
+			code, err := strconv.ParseUint(hex, 16, 32)
+			if err != nil {
+				l.checkNextLine()
+				return l.newToken(ErrorToken, fmt.Sprintf("invalid unicode escape \\u%v", hex), s)
+			}
+			b.WriteRune(rune(code))
+			l.loc.Column += 4
+		case r == '\\':
+			e, ok := escapes[l.currentRune()]
+			if !ok {
+				v := string(l.currentRune())
+				l.loc.Column++
+				l.checkNextLine()
+				return l.newToken(ErrorToken, fmt.Sprintf("invalid escape \\%v", v), s)
+			}
+			b.WriteRune(e)
+			l.loc.Column++
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	l.checkNextLine()
+	return l.newToken(ErrorToken, "unterminated string", s)
+}
+
+func (l *Lexer) lexBlockString() Token {
+	s := l.loc
+	l.loc.Column += 3
+
+	var b strings.Builder
+	for {
+		switch {
+		case l.isEndOfLine() && l.isLastLine():
+			return l.newToken(ErrorToken, "unterminated block string", s)
+		case l.isEndOfLine():
+			b.WriteRune('\n')
+			l.loc.Line++
+			l.loc.Column = 0
+			l.dSlice = []rune(l.lines[l.loc.Line])
+		case l.peek(`"""`):
+			l.loc.Column += 3
+			l.checkNextLine()
+			return l.newToken(StringToken, blockStringValue(b.String()), s)
+		case l.peek(`\"""`):
+			b.WriteString(`"""`)
+			l.loc.Column += 4
+		default:
+			b.WriteRune(l.currentRune())
+			l.loc.Column++
+		}
+	}
+}
+
+func leadingWhitespace(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+func blockStringValue(raw string) string {
+	lines := strings.Split(raw, "\n")
+
+	commonIndent := -1
+	for _, line := range lines[1:] {
+		indent := leadingWhitespace(line)
+		if indent < len(line) && (commonIndent == -1 || indent < commonIndent) {
+			commonIndent = indent
+		}
+	}
+	if commonIndent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) < commonIndent {
+				lines[i] = ""
+			} else {
+				lines[i] = lines[i][commonIndent:]
+			}
+		}
+	}
+
+	for len(lines) > 0 && leadingWhitespace(lines[0]) == len(lines[0]) {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && leadingWhitespace(lines[len(lines)-1]) == len(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+var punctuators = map[rune]TokenType{
+	'!': BangToken,
+	'$': DollarToken,
+	'&': AmpToken,
+	'(': LeftParenToken,
+	')': RightParenToken,
+	':': ColonToken,
+	'=': EqualsToken,
+	'@': AtToken,
+	'[': LeftBracketToken,
+	']': RightBracketToken,
+	'{': LeftCurlyToken,
+	'|': BarToken,
+	'}': RightCurlyToken,
+}
+
+// Lex sends the tokens of the document on c and closes it after the end
+// of file token.
 func (l *Lexer) Lex(c chan Token) {
+	defer close(c)
+	l.lex(func(t Token) {
+		c <- t
+	})
+}
+
+// Tokens returns all tokens of the document without the overhead of a
+// channel.
+func (l *Lexer) Tokens() []Token {
+	tokens := make([]Token, 0)
+	l.lex(func(t Token) {
+		tokens = append(tokens, t)
+	})
+	return tokens
+}
+
+func (l *Lexer) lex(emit func(Token)) {
 	for !l.isDone() {
 		r := l.currentRune()
-		switch {
-		case r == '{':
-			c <- l.newToken(LeftCurlyToken, "", l.loc)
-			l.increment()
-		case r == '}':
-			c <- l.newToken(RightCurlyToken, "", l.loc)
-			l.increment()
-		case r == ':':
-			c <- l.newToken(ColonToken, "", l.loc)
+		if tt, ok := punctuators[r]; ok {
+			emit(l.newToken(tt, "", l.loc))
 			l.increment()
-		case r == '(':
-			c <- l.newToken(LeftParenToken, "", l.loc)
-			l.increment()
-		case r == ')':
-			c <- l.newToken(RightParenToken, "", l.loc)
-			l.increment()
-		case r == ',':
-			c <- l.newToken(CommaToken, "", l.loc)
-			l.increment()
-		case r == '!':
-			c <- l.newToken(BangToken, "", l.loc)
-			l.increment()
-		case r == '@':
-			c <- l.newToken(AtToken, "", l.loc)
-			l.increment()
-		case r == '[':
-			c <- l.newToken(LeftBracketToken, "", l.loc)
-			l.increment()
-		case r == ']':
-			c <- l.newToken(RightBracketToken, "", l.loc)
-			l.increment()
-		case r == '|':
-			c <- l.newToken(BarToken, "", l.loc)
-			l.increment()
-		case unicode.IsSpace(r):
+			continue
+		}
+
+		switch {
+		case r == '.':
+			if !l.peek("...") {
+				emit(l.newToken(ErrorToken, "expected ... spread", l.loc))
+				l.increment()
+				continue
+			}
+			emit(l.newToken(SpreadToken, "", l.loc))
+			l.loc.Column += 3
+			l.checkNextLine()
+		case r == '#':
+			s := l.loc
+			l.loc.Column++
+			value := l.while(func(rune) bool { return true })
+			emit(l.newToken(CommentToken, value, s))
+		case r == '"':
+			emit(l.lexString())
+		case r == '-' || isDigit(r):
+			emit(l.lexNumber())
+		case isIgnored(r):
 			s := l.loc
-			w := l.while(unicode.IsSpace)
-			c <- l.newToken(WhitespaceToken, string(len(w)), s)
-		case isText(r):
+			w := l.while(isIgnored)
+			emit(l.newToken(WhitespaceToken, w, s))
+		case isNameStart(r):
 			s := l.loc
 			value := l.while(isText)
-			c <- l.newToken(TextToken, value, s)
+			emit(l.newToken(TextToken, value, s))
 		default:
-			c <- l.newToken(ErrorToken, fmt.Sprintf("unknown rune %v", string(r)), l.loc)
+			emit(l.newToken(ErrorToken, fmt.Sprintf("unknown rune %q", r), l.loc))
 			l.increment()
 		}
 	}
-	c <- l.newToken(EOFToken, "", l.loc)
-	close(c)
+	emit(l.newToken(EOFToken, "", l.loc))
 }
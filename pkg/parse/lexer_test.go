@@ -5,7 +5,7 @@ import (
 
 	"github.com/google/go-cmp/cmp/cmpopts"
 
-	"github.com/beauknowssoftware/go-gql-gen/internal/parse"
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -49,9 +49,6 @@ func TestLex(t *testing.T) {
 				{
 					TokenType: parse.BangToken,
 				},
-				{
-					TokenType: parse.CommaToken,
-				},
 				{
 					TokenType: parse.TextToken,
 					Value:     "b",
@@ -192,9 +189,6 @@ func TestLex(t *testing.T) {
 					TokenType: parse.TextToken,
 					Value:     "Int",
 				},
-				{
-					TokenType: parse.CommaToken,
-				},
 				{
 					TokenType: parse.TextToken,
 					Value:     "b",
@@ -729,7 +723,7 @@ func TestLex(t *testing.T) {
 
 			tokens := make([]parse.Token, 0)
 			for t := range c {
-				if t.TokenType != parse.WhitespaceToken {
+				if !t.TokenType.Ignored() {
 					tokens = append(tokens, t)
 				}
 			}
@@ -754,3 +748,216 @@ func TestLexComplex(t *testing.T) {
 		}
 	}
 }
+
+func tok(tt parse.TokenType, value string, line int, column int) parse.Token {
+	return parse.Token{TokenType: tt, Loc: parse.Loc{Line: line, Column: column}, Value: value}
+}
+
+func TestLexConformance(t *testing.T) {
+	tests := map[string]struct {
+		document       string
+		keepIgnored    bool
+		expectedTokens []parse.Token
+	}{
+		"byte order mark": {
+			document: "\uFEFFtype",
+			expectedTokens: []parse.Token{
+				tok(parse.TextToken, "type", 0, 1),
+				tok(parse.EOFToken, "", 0, 5),
+			},
+		},
+		"crlf line terminators": {
+			document: "a\r\nb\r\n",
+			expectedTokens: []parse.Token{
+				tok(parse.TextToken, "a", 0, 0),
+				tok(parse.TextToken, "b", 1, 0),
+				tok(parse.EOFToken, "", 2, 0),
+			},
+		},
+		"cr line terminators": {
+			document: "a\rb",
+			expectedTokens: []parse.Token{
+				tok(parse.TextToken, "a", 0, 0),
+				tok(parse.TextToken, "b", 1, 0),
+				tok(parse.EOFToken, "", 1, 1),
+			},
+		},
+		"mixed line terminators": {
+			document: "a\n\r\nb\rc",
+			expectedTokens: []parse.Token{
+				tok(parse.TextToken, "a", 0, 0),
+				tok(parse.TextToken, "b", 2, 0),
+				tok(parse.TextToken, "c", 3, 0),
+				tok(parse.EOFToken, "", 3, 1),
+			},
+		},
+		"leading blank lines": {
+			document: "\n\n  a",
+			expectedTokens: []parse.Token{
+				tok(parse.TextToken, "a", 2, 2),
+				tok(parse.EOFToken, "", 2, 3),
+			},
+		},
+		"empty document": {
+			document: "",
+			expectedTokens: []parse.Token{
+				tok(parse.EOFToken, "", 0, 0),
+			},
+		},
+		"commas are ignored": {
+			document: "a,b,,c",
+			expectedTokens: []parse.Token{
+				tok(parse.TextToken, "a", 0, 0),
+				tok(parse.TextToken, "b", 0, 2),
+				tok(parse.TextToken, "c", 0, 5),
+				tok(parse.EOFToken, "", 0, 6),
+			},
+		},
+		"ignored tokens": {
+			document:    "a ,\t\uFEFF# comment, with comma\nb",
+			keepIgnored: true,
+			expectedTokens: []parse.Token{
+				tok(parse.TextToken, "a", 0, 0),
+				tok(parse.WhitespaceToken, " ,\t\uFEFF", 0, 1),
+				tok(parse.CommentToken, " comment, with comma", 0, 5),
+				tok(parse.TextToken, "b", 1, 0),
+				tok(parse.EOFToken, "", 1, 1),
+			},
+		},
+		"punctuators": {
+			document: "! $ & ( ) ... : = @ [ ] { | }",
+			expectedTokens: []parse.Token{
+				tok(parse.BangToken, "", 0, 0),
+				tok(parse.DollarToken, "", 0, 2),
+				tok(parse.AmpToken, "", 0, 4),
+				tok(parse.LeftParenToken, "", 0, 6),
+				tok(parse.RightParenToken, "", 0, 8),
+				tok(parse.SpreadToken, "", 0, 10),
+				tok(parse.ColonToken, "", 0, 14),
+				tok(parse.EqualsToken, "", 0, 16),
+				tok(parse.AtToken, "", 0, 18),
+				tok(parse.LeftBracketToken, "", 0, 20),
+				tok(parse.RightBracketToken, "", 0, 22),
+				tok(parse.LeftCurlyToken, "", 0, 24),
+				tok(parse.BarToken, "", 0, 26),
+				tok(parse.RightCurlyToken, "", 0, 28),
+				tok(parse.EOFToken, "", 0, 29),
+			},
+		},
+		"adjacent punctuators": {
+			document: "[a!]!...b",
+			expectedTokens: []parse.Token{
+				tok(parse.LeftBracketToken, "", 0, 0),
+				tok(parse.TextToken, "a", 0, 1),
+				tok(parse.BangToken, "", 0, 2),
+				tok(parse.RightBracketToken, "", 0, 3),
+				tok(parse.BangToken, "", 0, 4),
+				tok(parse.SpreadToken, "", 0, 5),
+				tok(parse.TextToken, "b", 0, 8),
+				tok(parse.EOFToken, "", 0, 9),
+			},
+		},
+		"incomplete spread": {
+			document: "..a",
+			expectedTokens: []parse.Token{
+				tok(parse.ErrorToken, "expected ... spread", 0, 0),
+				tok(parse.ErrorToken, "expected ... spread", 0, 1),
+				tok(parse.TextToken, "a", 0, 2),
+				tok(parse.EOFToken, "", 0, 3),
+			},
+		},
+		"names": {
+			document: "_a1 B_2",
+			expectedTokens: []parse.Token{
+				tok(parse.TextToken, "_a1", 0, 0),
+				tok(parse.TextToken, "B_2", 0, 4),
+				tok(parse.EOFToken, "", 0, 7),
+			},
+		},
+		"non ascii name": {
+			document: "é",
+			expectedTokens: []parse.Token{
+				tok(parse.ErrorToken, `unknown rune 'é'`, 0, 0),
+				tok(parse.EOFToken, "", 0, 1),
+			},
+		},
+		"vertical tab is not white space": {
+			document: "\v",
+			expectedTokens: []parse.Token{
+				tok(parse.ErrorToken, `unknown rune '\v'`, 0, 0),
+				tok(parse.EOFToken, "", 0, 1),
+			},
+		},
+		"numbers": {
+			document: "0 -1 12 1.5 -0.25 1e10 2E-3 6.02e+23",
+			expectedTokens: []parse.Token{
+				tok(parse.IntToken, "0", 0, 0),
+				tok(parse.IntToken, "-1", 0, 2),
+				tok(parse.IntToken, "12", 0, 5),
+				tok(parse.FloatToken, "1.5", 0, 8),
+				tok(parse.FloatToken, "-0.25", 0, 12),
+				tok(parse.FloatToken, "1e10", 0, 18),
+				tok(parse.FloatToken, "2E-3", 0, 23),
+				tok(parse.FloatToken, "6.02e+23", 0, 28),
+				tok(parse.EOFToken, "", 0, 36),
+			},
+		},
+		"invalid numbers": {
+			document: "01 1. 1e 1a 1.2.3 -",
+			expectedTokens: []parse.Token{
+				tok(parse.ErrorToken, "invalid number 01", 0, 0),
+				tok(parse.ErrorToken, "invalid number 1.", 0, 3),
+				tok(parse.ErrorToken, "invalid number 1e", 0, 6),
+				tok(parse.ErrorToken, "invalid number 1a", 0, 9),
+				tok(parse.ErrorToken, "invalid number 1.2.3", 0, 12),
+				tok(parse.ErrorToken, "invalid number -", 0, 18),
+				tok(parse.EOFToken, "", 0, 19),
+			},
+		},
+		"strings": {
+			document: `"" "a, b # c" "\"\\\/\b\f\n\r\t" "é"`,
+			expectedTokens: []parse.Token{
+				tok(parse.StringToken, "", 0, 0),
+				tok(parse.StringToken, "a, b # c", 0, 3),
+				tok(parse.StringToken, "\"\\/\b\f\n\r\t", 0, 14),
+				tok(parse.StringToken, "é", 0, 33),
+				tok(parse.EOFToken, "", 0, 36),
+			},
+		},
+		"string cannot span lines": {
+			document: "\"a\r\nb",
+			expectedTokens: []parse.Token{
+				tok(parse.ErrorToken, "unterminated string", 0, 0),
+				tok(parse.TextToken, "b", 1, 0),
+				tok(parse.EOFToken, "", 1, 1),
+			},
+		},
+		"block string line terminators": {
+			document: "\"\"\"\r\n  a\r\n\r    b\n\"\"\"",
+			expectedTokens: []parse.Token{
+				tok(parse.StringToken, "a\n\n  b", 0, 0),
+				tok(parse.EOFToken, "", 4, 3),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			l := parse.NewLexer(test.document)
+
+			c := make(chan parse.Token)
+			go l.Lex(c)
+
+			tokens := make([]parse.Token, 0)
+			for t := range c {
+				if test.keepIgnored || !t.TokenType.Ignored() {
+					tokens = append(tokens, t)
+				}
+			}
+
+			if diff := cmp.Diff(test.expectedTokens, tokens); diff != "" {
+				t.Fatalf("mismatch (expected, got) %v", diff)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ type Node interface {
 }
 
 type LeafNode struct{}
+
 func (n LeafNode) Children() []Node {
 	return nil
 }
@@ -15,7 +16,7 @@ type NodeLoc struct {
 }
 
 func (n NodeLoc) Loc() Loc {
-	return n.Loc()
+	return n.NodeLoc
 }
 
 type DocumentNode struct {
@@ -44,61 +45,115 @@ type DefinitionNode interface {
 	Node
 }
 
+func childNodes(groups ...[]Node) []Node {
+	children := make([]Node, 0)
+	for _, g := range groups {
+		for _, n := range g {
+			if n != nil {
+				children = append(children, n)
+			}
+		}
+	}
+	return children
+}
+
 type DirectiveDefNode struct {
 	NodeLoc
-	LeafNode
-	Name   string
-	Targets []string
+	Description string
+	Name        string
+	Params      []Node
+	Repeatable  bool
+	Targets     []string
+}
+
+func (n DirectiveDefNode) Children() []Node {
+	return childNodes(n.Params)
 }
 
 type TypeDefNode struct {
 	NodeLoc
-	Name   string
-	Fields []Node
-	Input  bool
+	Description string
+	Name        string
+	Fields      []Node
+	Input       bool
+	Interface   bool
+	Interfaces  []string
+	Directives  []Node
 }
 
 func (n TypeDefNode) Children() []Node {
-	children := make([]Node, len(n.Fields), len(n.Fields))
-	for i, n := range n.Fields {
-		children[i] = n
-	}
-	return children
+	return childNodes(n.Fields, n.Directives)
+}
+
+type EnumDefNode struct {
+	NodeLoc
+	Description string
+	Name        string
+	Values      []Node
+	Directives  []Node
+}
+
+func (n EnumDefNode) Children() []Node {
+	return childNodes(n.Values, n.Directives)
+}
+
+type EnumValueNode struct {
+	NodeLoc
+	Description string
+	Name        string
+	Directives  []Node
+}
+
+func (n EnumValueNode) Children() []Node {
+	return childNodes(n.Directives)
+}
+
+type ScalarDefNode struct {
+	NodeLoc
+	Description string
+	Name        string
+	Directives  []Node
+}
+
+func (n ScalarDefNode) Children() []Node {
+	return childNodes(n.Directives)
+}
+
+type UnionDefNode struct {
+	NodeLoc
+	Description string
+	Name        string
+	Types       []string
+	Directives  []Node
+}
+
+func (n UnionDefNode) Children() []Node {
+	return childNodes(n.Directives)
 }
 
 type SchemaNode struct {
 	NodeLoc
-	Fields []Node
+	Description string
+	Fields      []Node
+	Directives  []Node
 }
 
 func (n SchemaNode) Children() []Node {
-	children := make([]Node, len(n.Fields), len(n.Fields))
-	for i, n := range n.Fields {
-		children[i] = n
-	}
-	return children
+	return childNodes(n.Fields, n.Directives)
 }
 
 type FieldNode struct {
 	NodeLoc
-	Name       string
-	Type       Node
-	Params     []Node
-	Directives []Node
+	Description  string
+	Name         string
+	Type         Node
+	Params       []Node
+	DefaultValue Node
+	Directives   []Node
 }
 
 func (n FieldNode) Children() []Node {
-	paramCount := len(n.Params)
-	childCount := paramCount + len(n.Directives) + 1
-	children := make([]Node, childCount, childCount)
-	children[0] = n.Type
-	for i, n := range n.Params {
-		children[i+1] = n
-	}
-	for i, n := range n.Directives {
-		children[i+1+paramCount] = n
-	}
-	return children
+	return childNodes([]Node{n.Type}, n.Params, []Node{n.DefaultValue}, n.Directives)
 }
 
 type TypeNode struct {
@@ -112,18 +167,71 @@ type TypeNode struct {
 
 type ParamNode struct {
 	NodeLoc
-	Name string
-	Type Node
+	Description  string
+	Name         string
+	Type         Node
+	DefaultValue Node
+	Directives   []Node
 }
 
 func (n ParamNode) Children() []Node {
-	return []Node{n.Type}
+	return childNodes([]Node{n.Type, n.DefaultValue}, n.Directives)
 }
 
 type DirectiveNode struct {
+	NodeLoc
+	Name      string
+	Arguments []Node
+}
+
+func (n DirectiveNode) Children() []Node {
+	return childNodes(n.Arguments)
+}
+
+type ArgumentNode struct {
+	NodeLoc
+	Name  string
+	Value Node
+}
+
+func (n ArgumentNode) Children() []Node {
+	return []Node{n.Value}
+}
+
+type ValueKind int
+
+const (
+	IntValue ValueKind = iota
+	FloatValue
+	StringValue
+	BooleanValue
+	NullValue
+	EnumValue
+)
+
+type ValueNode struct {
 	NodeLoc
 	LeafNode
-	Name string
+	Kind  ValueKind
+	Value string
+}
+
+type ListValueNode struct {
+	NodeLoc
+	Values []Node
+}
+
+func (n ListValueNode) Children() []Node {
+	return childNodes(n.Values)
+}
+
+type ObjectValueNode struct {
+	NodeLoc
+	Fields []Node
+}
+
+func (n ObjectValueNode) Children() []Node {
+	return childNodes(n.Fields)
 }
 
 type TokenNode struct {
@@ -132,4 +240,3 @@ type TokenNode struct {
 	TokenType TokenType
 	Value     string
 }
-
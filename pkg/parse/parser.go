@@ -6,28 +6,47 @@ import (
 )
 
 type Parser struct {
-	l      Lexer
-	tokens []Token
-	i      int
+	l        Lexer
+	tokens   []Token
+	i        int
+	failure  error
+	failureI int
+	depth    int
 }
 
 func New(l Lexer) Parser {
 	return Parser{l: l}
 }
 
+// newTokenParser returns a parser for tokens that were already lexed.
+func newTokenParser(tokens []Token) Parser {
+	return Parser{tokens: tokens}
+}
+
 func (p *Parser) current() Token {
+	if p.i >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
 	return p.tokens[p.i]
 }
 
 func (p *Parser) consume() {
 	if p.i < len(p.tokens)-1 {
 		p.i++
-		for p.current().TokenType == WhitespaceToken {
+		for p.current().TokenType.Ignored() && p.i < len(p.tokens)-1 {
 			p.i++
 		}
 	}
 }
 
+func (p *Parser) fail(err error) (Node, error) {
+	if p.failure == nil || p.i >= p.failureI {
+		p.failure = err
+		p.failureI = p.i
+	}
+	return nil, err
+}
+
 func (p Parser) nodeLoc() NodeLoc {
 	return NodeLoc{p.current().Loc}
 }
@@ -43,7 +62,7 @@ func keyword(v string) parserPart {
 		nodeLoc := p.nodeLoc()
 
 		if p.current().TokenType != TextToken || p.current().Value != v {
-			return nil, fmt.Errorf("expected keyword %v keyword got %v (%v) token", v, p.current().TokenType, p.current().Value)
+			return p.fail(fmt.Errorf("expected keyword %v keyword got %v (%v) token", v, p.current().TokenType, p.current().Value))
 		}
 		p.consume()
 
@@ -56,19 +75,21 @@ func token(tt TokenType) parserPart {
 		nodeLoc := p.nodeLoc()
 
 		if p.current().TokenType != tt {
-			return nil, fmt.Errorf("expected %v token got %v token", tt, p.current().TokenType)
+			return p.fail(fmt.Errorf("expected %v token got %v token", tt, p.current().TokenType))
 		}
 		t := p.current()
 		p.consume()
 
-		return TokenNode{nodeLoc, LeafNode{},tt, t.Value}, nil
+		return TokenNode{nodeLoc, LeafNode{}, tt, t.Value}, nil
 	}
 }
 
 func maybe(pp parserPart) parserPart {
 	return func(p *Parser) (Node, error) {
+		start := p.i
 		n, err := pp(p)
 		if err != nil {
+			p.i = start
 			return nil, nil
 		}
 		return n, nil
@@ -102,8 +123,10 @@ func multi(pp parserPart) parserPart {
 		nodes := make([]Node, 0)
 
 		for {
+			start := p.i
 			n, err := pp(p)
 			if err != nil {
+				p.i = start
 				break
 			}
 			nodes = append(nodes, n)
@@ -145,152 +168,412 @@ func multiSep(pp parserPart, sep parserPart) parserPart {
 
 func choice(pps ...parserPart) parserPart {
 	return func(p *Parser) (Node, error) {
+		start := p.i
 		for _, pp := range pps {
 			n, err := pp(p)
 			if err == nil {
 				return n, nil
 			}
+			p.i = start
 		}
 		return nil, errors.New("cannot match keyword")
 	}
 }
 
-var parseParameter = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
-	return ParamNode{
-		nodeLoc,
-		nodes[0].(TokenNode).Value,
-		nodes[2],
-	}, nil
-}, identifier, token(ColonToken), parseType)
+var identifier = token(TextToken)
+var required = token(BangToken)
 
-var parseParameterList = multiSep(parseParameter, token(CommaToken))
+// parts gives transformers checked access to the nodes matched by seq, so
+// a mismatch surfaces as a parse error instead of a panic.
+type parts struct {
+	nodes []Node
+	err   error
+}
 
-var parseParameters = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
-	return nodes[1], nil
-}, token(LeftParenToken), parseParameterList, token(RightParenToken))
+func (ps *parts) fail(i int, expected string) {
+	if ps.err == nil {
+		ps.err = fmt.Errorf("expected %v as part %v of sequence", expected, i)
+	}
+}
 
-var identifier = token(TextToken)
-var required = token(BangToken)
+func (ps *parts) node(i int) Node {
+	if i >= len(ps.nodes) {
+		ps.fail(i, "node")
+		return nil
+	}
+	return ps.nodes[i]
+}
+
+func (ps *parts) present(i int) bool {
+	return ps.node(i) != nil
+}
+
+func (ps *parts) value(i int) string {
+	n := ps.node(i)
+	if n == nil {
+		return ""
+	}
+	tn, ok := n.(TokenNode)
+	if !ok {
+		ps.fail(i, "token")
+	}
+	return tn.Value
+}
+
+func (ps *parts) list(i int) []Node {
+	n := ps.node(i)
+	if n == nil {
+		return nil
+	}
+	mn, ok := n.(MultiNode)
+	if !ok {
+		ps.fail(i, "list")
+	}
+	return mn.Nodes
+}
+
+func (ps *parts) optionalList(i int) []Node {
+	if nodes := ps.list(i); len(nodes) > 0 {
+		return nodes
+	}
+	return nil
+}
+
+func (ps *parts) names(i int) []string {
+	nodes := ps.optionalList(i)
+	if nodes == nil {
+		return nil
+	}
+	names := make([]string, len(nodes), len(nodes))
+	for j, node := range nodes {
+		tn, ok := node.(TokenNode)
+		if !ok {
+			ps.fail(i, "token list")
+		}
+		names[j] = tn.Value
+	}
+	return names
+}
+
+func first(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
+	ps := &parts{nodes: nodes}
+	return ps.node(0), ps.err
+}
+
+func second(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
+	ps := &parts{nodes: nodes}
+	return ps.node(1), ps.err
+}
+
+var parseDescription = maybe(token(StringToken))
+
+// maxValueDepth bounds the nesting of list and object values so hostile
+// input cannot exhaust the stack.
+const maxValueDepth = 64
+
+var parseValue parserPart
+
+func value(p *Parser) (Node, error) {
+	if p.depth >= maxValueDepth {
+		return p.fail(fmt.Errorf("values nested deeper than %v levels", maxValueDepth))
+	}
+	p.depth++
+	defer func() { p.depth-- }()
+	return parseValue(p)
+}
+
+func scalarValue(tt TokenType, kind ValueKind) parserPart {
+	return seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
+		ps := &parts{nodes: nodes}
+		n := ValueNode{nodeLoc, LeafNode{}, kind, ps.value(0)}
+		return n, ps.err
+	}, token(tt))
+}
+
+var parseNameValue = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
+	ps := &parts{nodes: nodes}
+	v := ps.value(0)
+	switch v {
+	case "true", "false":
+		return ValueNode{nodeLoc, LeafNode{}, BooleanValue, v}, ps.err
+	case "null":
+		return ValueNode{nodeLoc, LeafNode{}, NullValue, v}, ps.err
+	default:
+		return ValueNode{nodeLoc, LeafNode{}, EnumValue, v}, ps.err
+	}
+}, identifier)
+
+var parseListValue = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
+	ps := &parts{nodes: nodes}
+	n := ListValueNode{nodeLoc, ps.optionalList(1)}
+	return n, ps.err
+}, token(LeftBracketToken), multi(value), token(RightBracketToken))
+
+var parseArgument = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
+	ps := &parts{nodes: nodes}
+	n := ArgumentNode{
+		nodeLoc,
+		ps.value(0),
+		ps.node(2),
+	}
+	return n, ps.err
+}, identifier, token(ColonToken), value)
+
+var parseObjectValue = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
+	ps := &parts{nodes: nodes}
+	n := ObjectValueNode{nodeLoc, ps.optionalList(1)}
+	return n, ps.err
+}, token(LeftCurlyToken), multi(parseArgument), token(RightCurlyToken))
+
+func init() {
+	parseValue = choice(
+		scalarValue(StringToken, StringValue),
+		scalarValue(IntToken, IntValue),
+		scalarValue(FloatToken, FloatValue),
+		parseNameValue,
+		parseListValue,
+		parseObjectValue,
+	)
+}
+
+var parseDefaultValue = seq(second, token(EqualsToken), value)
+
+var parseArguments = seq(second, token(LeftParenToken), multi(parseArgument), token(RightParenToken))
 
 var parseDirective = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
-	return DirectiveNode{
+	ps := &parts{nodes: nodes}
+	n := DirectiveNode{
 		nodeLoc,
-		LeafNode{},
-		nodes[1].(TokenNode).Value,
-	}, nil
-}, token(AtToken), identifier)
+		ps.value(1),
+		ps.optionalList(2),
+	}
+	return n, ps.err
+}, token(AtToken), identifier, maybe(parseArguments))
+
+var parseDirectives = multi(parseDirective)
 
 var parseArrayType = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
-	return TypeNode{
+	ps := &parts{nodes: nodes}
+	n := TypeNode{
 		nodeLoc,
 		LeafNode{},
-		nodes[1].(TokenNode).Value,
-		nodes[4] != nil,
+		ps.value(1),
+		ps.present(4),
 		true,
-		nodes[2] != nil,
-	}, nil
-}, token(LeftBracketToken), identifier, maybe(required), maybe(token(RightBracketToken)), maybe(required))
+		ps.present(2),
+	}
+	return n, ps.err
+}, token(LeftBracketToken), identifier, maybe(required), token(RightBracketToken), maybe(required))
 
 var parseSingleType = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
-	return TypeNode{
+	ps := &parts{nodes: nodes}
+	n := TypeNode{
 		nodeLoc,
 		LeafNode{},
-		nodes[0].(TokenNode).Value,
-		nodes[1] != nil,
+		ps.value(0),
+		ps.present(1),
 		false,
 		false,
-	}, nil
+	}
+	return n, ps.err
 }, identifier, maybe(required))
 
 var parseType = choice(parseArrayType, parseSingleType)
 
-var parseField = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
-	f := FieldNode{
+var parseParameter = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
+	ps := &parts{nodes: nodes}
+	n := ParamNode{
 		nodeLoc,
-		nodes[0].(TokenNode).Value,
-		nodes[3],
-		nil,
-		nil,
-	}
-	if nodes[1] != nil {
-		f.Params = nodes[1].(MultiNode).Nodes
+		ps.value(0),
+		ps.value(1),
+		ps.node(3),
+		ps.node(4),
+		ps.optionalList(5),
 	}
-	if directives := nodes[4].(MultiNode).Nodes; len(directives) > 0 {
-		f.Directives = directives
+	return n, ps.err
+}, parseDescription, identifier, token(ColonToken), parseType, maybe(parseDefaultValue), parseDirectives)
+
+var parseParameterList = multi(parseParameter)
+
+var parseParameters = seq(second, token(LeftParenToken), parseParameterList, token(RightParenToken))
+
+var parseField = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
+	ps := &parts{nodes: nodes}
+	n := FieldNode{
+		nodeLoc,
+		ps.value(0),
+		ps.value(1),
+		ps.node(4),
+		ps.optionalList(2),
+		ps.node(5),
+		ps.optionalList(6),
 	}
-	return f, nil
-}, identifier, maybe(parseParameters), token(ColonToken), parseType, multi(parseDirective))
+	return n, ps.err
+}, parseDescription, identifier, maybe(parseParameters), token(ColonToken), parseType, maybe(parseDefaultValue), parseDirectives)
+
+var parseFields = seq(second, token(LeftCurlyToken), multi(parseField), token(RightCurlyToken))
 
 var schemaKeyword = keyword("schema")
 
 var parseSchema = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
-	return SchemaNode{
+	ps := &parts{nodes: nodes}
+	n := SchemaNode{
 		nodeLoc,
-		nodes[2].(MultiNode).Nodes,
-	}, nil
-}, schemaKeyword, token(LeftCurlyToken), multi(parseField), token(RightCurlyToken))
+		ps.value(0),
+		ps.list(3),
+		ps.optionalList(2),
+	}
+	return n, ps.err
+}, parseDescription, schemaKeyword, parseDirectives, parseFields)
 
 var directiveKeyword = keyword("directive")
 var onKeyword = keyword("on")
+var repeatableKeyword = keyword("repeatable")
 
 var parseDirectiveTargetList = multiSep(identifier, token(BarToken))
 
 var parseDirectiveDef = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
-	targetNodes := nodes[4].(MultiNode).Nodes
-	targets := make([]string, len(targetNodes), len(targetNodes))
-	for i,node := range targetNodes {
-		targets[i] = node.(TokenNode).Value
-	}
-	return DirectiveDefNode{
+	ps := &parts{nodes: nodes}
+	n := DirectiveDefNode{
 		nodeLoc,
-		LeafNode{},
-		nodes[2].(TokenNode).Value,
-		targets,
-	}, nil
-}, directiveKeyword, token(AtToken), identifier, onKeyword, parseDirectiveTargetList)
+		ps.value(0),
+		ps.value(3),
+		ps.optionalList(4),
+		ps.present(5),
+		ps.names(8),
+	}
+	return n, ps.err
+}, parseDescription, directiveKeyword, token(AtToken), identifier, maybe(parseParameters), maybe(repeatableKeyword), onKeyword, maybe(token(BarToken)), parseDirectiveTargetList)
+
+var implementsKeyword = keyword("implements")
+
+var parseImplements = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
+	ps := &parts{nodes: nodes}
+	return ps.node(2), ps.err
+}, implementsKeyword, maybe(token(AmpToken)), multiSep(identifier, token(AmpToken)))
+
+func objectDef(input bool, iface bool) transformer {
+	return func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
+		ps := &parts{nodes: nodes}
+		n := TypeDefNode{
+			nodeLoc,
+			ps.value(0),
+			ps.value(2),
+			ps.list(5),
+			input,
+			iface,
+			ps.names(3),
+			ps.optionalList(4),
+		}
+		return n, ps.err
+	}
+}
 
 var typeKeyword = keyword("type")
 
-var parseTypeDef = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
-	return TypeDefNode{
-		nodeLoc,
-		nodes[1].(TokenNode).Value,
-		nodes[3].(MultiNode).Nodes,
-		false,
-	}, nil
-}, typeKeyword, identifier, token(LeftCurlyToken), multi(parseField), token(RightCurlyToken))
+var parseTypeDef = seq(objectDef(false, false), parseDescription, typeKeyword, identifier, maybe(parseImplements), parseDirectives, parseFields)
+
+var interfaceKeyword = keyword("interface")
+
+var parseInterface = seq(objectDef(false, true), parseDescription, interfaceKeyword, identifier, maybe(parseImplements), parseDirectives, parseFields)
 
 var inputKeyword = keyword("input")
 
-var parseInput = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
-	return TypeDefNode{
+var parseInput = seq(objectDef(true, false), parseDescription, inputKeyword, identifier, nothing, parseDirectives, parseFields)
+
+var parseEnumValue = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
+	ps := &parts{nodes: nodes}
+	n := EnumValueNode{
 		nodeLoc,
-		nodes[1].(TokenNode).Value,
-		nodes[3].(MultiNode).Nodes,
-		true,
-	}, nil
-}, inputKeyword, identifier, token(LeftCurlyToken), multi(parseField), token(RightCurlyToken))
+		ps.value(0),
+		ps.value(1),
+		ps.optionalList(2),
+	}
+	return n, ps.err
+}, parseDescription, identifier, parseDirectives)
+
+var enumKeyword = keyword("enum")
+
+var parseEnum = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
+	ps := &parts{nodes: nodes}
+	n := EnumDefNode{
+		nodeLoc,
+		ps.value(0),
+		ps.value(2),
+		ps.list(5),
+		ps.optionalList(3),
+	}
+	return n, ps.err
+}, parseDescription, enumKeyword, identifier, parseDirectives, token(LeftCurlyToken), multi(parseEnumValue), token(RightCurlyToken))
+
+var scalarKeyword = keyword("scalar")
+
+var parseScalar = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
+	ps := &parts{nodes: nodes}
+	n := ScalarDefNode{
+		nodeLoc,
+		ps.value(0),
+		ps.value(2),
+		ps.optionalList(3),
+	}
+	return n, ps.err
+}, parseDescription, scalarKeyword, identifier, parseDirectives)
+
+var unionKeyword = keyword("union")
 
-var parseDefinition = choice(parseTypeDef, parseInput, parseSchema, parseDirectiveDef)
+var parseUnion = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
+	ps := &parts{nodes: nodes}
+	n := UnionDefNode{
+		nodeLoc,
+		ps.value(0),
+		ps.value(2),
+		ps.names(6),
+		ps.optionalList(3),
+	}
+	return n, ps.err
+}, parseDescription, unionKeyword, identifier, parseDirectives, token(EqualsToken), maybe(token(BarToken)), multiSep(identifier, token(BarToken)))
+
+var parseDefinition = choice(parseTypeDef, parseInput, parseInterface, parseEnum, parseScalar, parseUnion, parseSchema, parseDirectiveDef)
 
 var parseDocument = seq(func(nodeLoc NodeLoc, nodes ...Node) (Node, error) {
-	return DocumentNode{nodeLoc, nodes[0].(MultiNode).Nodes}, nil
+	ps := &parts{nodes: nodes}
+	n := DocumentNode{nodeLoc, ps.list(0)}
+	return n, ps.err
 }, multi(parseDefinition), token(EOFToken))
 
+var parseValueDocument = seq(first, value, token(EOFToken))
+
 type Error struct {
 	Error error
 	Token Token
 }
 
 func (p *Parser) Parse() (Node, *Error) {
-	c := make(chan Token)
-	go p.l.Lex(c)
-	for t := range c {
-		p.tokens = append(p.tokens, t)
+	return p.run(parseDocument)
+}
+
+// ParseValue parses a single input value literal, such as a default value.
+func (p *Parser) ParseValue() (Node, *Error) {
+	return p.run(parseValueDocument)
+}
+
+func (p *Parser) run(pp parserPart) (Node, *Error) {
+	if p.tokens == nil {
+		p.tokens = p.l.Tokens()
+	}
+	if len(p.tokens) == 0 {
+		return nil, &Error{errors.New("lexer produced no tokens"), Token{TokenType: EOFToken}}
+	}
+
+	for p.current().TokenType.Ignored() && p.i < len(p.tokens)-1 {
+		p.i++
 	}
 
-	d, err := parseDocument(p)
+	d, err := pp(p)
 	if err != nil {
+		if p.failure != nil && p.failureI >= p.i {
+			return nil, &Error{p.failure, p.tokens[p.failureI]}
+		}
 		return nil, &Error{err, p.current()}
 	}
 	if p.i < len(p.tokens)-1 {
@@ -5,7 +5,7 @@ import (
 
 	"github.com/google/go-cmp/cmp/cmpopts"
 
-	"github.com/beauknowssoftware/go-gql-gen/internal/parse"
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -324,6 +324,77 @@ func TestParse(t *testing.T) {
 				},
 			},
 		},
+		"enum.graphqls": {
+			expectedAST: parse.DocumentNode{
+				Definitions: []parse.Node{
+					parse.EnumDefNode{
+						Description: "The state of a user.",
+						Name:        "Status",
+						Values: []parse.Node{
+							parse.EnumValueNode{
+								Description: "Can sign in.",
+								Name:        "ACTIVE",
+							},
+							parse.EnumValueNode{
+								Name: "DISABLED",
+								Directives: []parse.Node{
+									parse.DirectiveNode{
+										Name: "deprecated",
+										Arguments: []parse.Node{
+											parse.ArgumentNode{
+												Name: "reason",
+												Value: parse.ValueNode{
+													Kind:  parse.StringValue,
+													Value: "Delete the user instead.",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+						Directives: []parse.Node{
+							parse.DirectiveNode{
+								Name: "aws_api_key",
+							},
+						},
+					},
+					parse.TypeDefNode{
+						Name: "Query",
+						Fields: []parse.Node{
+							parse.FieldNode{
+								Name: "status",
+								Type: parse.TypeNode{
+									Name: "Status",
+								},
+								Params: []parse.Node{
+									parse.ParamNode{
+										Name: "is",
+										Type: parse.TypeNode{
+											Name: "Status",
+										},
+										DefaultValue: parse.ValueNode{
+											Kind:  parse.EnumValue,
+											Value: "ACTIVE",
+										},
+									},
+								},
+							},
+						},
+					},
+					parse.SchemaNode{
+						Fields: []parse.Node{
+							parse.FieldNode{
+								Name: "query",
+								Type: parse.TypeNode{
+									Name: "Query",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		"ping.graphqls": {
 			expectedAST: parse.DocumentNode{
 				Definitions: []parse.Node{
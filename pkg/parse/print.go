@@ -0,0 +1,259 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+)
+
+type printer struct {
+	b strings.Builder
+}
+
+func (pr *printer) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&pr.b, format, args...)
+}
+
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteRune('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteRune('"')
+	return b.String()
+}
+
+func (pr *printer) description(d string, indent string) {
+	if d == "" {
+		return
+	}
+	if !strings.Contains(d, "\n") {
+		pr.printf("%v%v\n", indent, quote(d))
+		return
+	}
+	pr.printf("%v\"\"\"\n", indent)
+	for _, line := range strings.Split(d, "\n") {
+		if line == "" {
+			pr.printf("\n")
+			continue
+		}
+		pr.printf("%v%v\n", indent, strings.Replace(line, `"""`, `\"""`, -1))
+	}
+	pr.printf("%v\"\"\"\n", indent)
+}
+
+func (pr *printer) value(n Node) {
+	switch vn := n.(type) {
+	case ValueNode:
+		if vn.Kind == StringValue {
+			pr.printf("%v", quote(vn.Value))
+		} else {
+			pr.printf("%v", vn.Value)
+		}
+	case ListValueNode:
+		pr.printf("[")
+		for i, v := range vn.Values {
+			if i > 0 {
+				pr.printf(", ")
+			}
+			pr.value(v)
+		}
+		pr.printf("]")
+	case ObjectValueNode:
+		pr.arguments("{", vn.Fields, "}")
+	}
+}
+
+func (pr *printer) arguments(open string, nodes []Node, close string) {
+	pr.b.WriteString(open)
+	for i, n := range nodes {
+		if i > 0 {
+			pr.printf(", ")
+		}
+		an := n.(ArgumentNode)
+		pr.printf("%v: ", an.Name)
+		pr.value(an.Value)
+	}
+	pr.b.WriteString(close)
+}
+
+func (pr *printer) directives(nodes []Node) {
+	for _, n := range nodes {
+		dn := n.(DirectiveNode)
+		pr.printf(" @%v", dn.Name)
+		if len(dn.Arguments) > 0 {
+			pr.arguments("(", dn.Arguments, ")")
+		}
+	}
+}
+
+func (pr *printer) typeRef(n Node) {
+	tn := n.(TypeNode)
+	if !tn.Multiple {
+		pr.printf("%v", tn.Name)
+	} else if tn.NonNullElements {
+		pr.printf("[%v!]", tn.Name)
+	} else {
+		pr.printf("[%v]", tn.Name)
+	}
+	if tn.Required {
+		pr.printf("!")
+	}
+}
+
+func (pr *printer) params(nodes []Node) {
+	if len(nodes) == 0 {
+		return
+	}
+	pr.printf("(")
+	for i, n := range nodes {
+		if i > 0 {
+			pr.printf(", ")
+		}
+		pn := n.(ParamNode)
+		if pn.Description != "" {
+			pr.printf("%v ", quote(pn.Description))
+		}
+		pr.printf("%v: ", pn.Name)
+		pr.typeRef(pn.Type)
+		if pn.DefaultValue != nil {
+			pr.printf(" = ")
+			pr.value(pn.DefaultValue)
+		}
+		pr.directives(pn.Directives)
+	}
+	pr.printf(")")
+}
+
+func (pr *printer) fields(nodes []Node) {
+	pr.printf(" {\n")
+	for _, n := range nodes {
+		fn := n.(FieldNode)
+		pr.description(fn.Description, "  ")
+		pr.printf("  %v", fn.Name)
+		pr.params(fn.Params)
+		pr.printf(": ")
+		pr.typeRef(fn.Type)
+		if fn.DefaultValue != nil {
+			pr.printf(" = ")
+			pr.value(fn.DefaultValue)
+		}
+		pr.directives(fn.Directives)
+		pr.printf("\n")
+	}
+	pr.printf("}\n")
+}
+
+func (pr *printer) definition(n Node) {
+	switch dn := n.(type) {
+	case TypeDefNode:
+		pr.description(dn.Description, "")
+		switch {
+		case dn.Input:
+			pr.printf("input %v", dn.Name)
+		case dn.Interface:
+			pr.printf("interface %v", dn.Name)
+		default:
+			pr.printf("type %v", dn.Name)
+		}
+		if len(dn.Interfaces) > 0 {
+			pr.printf(" implements %v", strings.Join(dn.Interfaces, " & "))
+		}
+		pr.directives(dn.Directives)
+		pr.fields(dn.Fields)
+	case EnumDefNode:
+		pr.description(dn.Description, "")
+		pr.printf("enum %v", dn.Name)
+		pr.directives(dn.Directives)
+		pr.printf(" {\n")
+		for _, n := range dn.Values {
+			vn := n.(EnumValueNode)
+			pr.description(vn.Description, "  ")
+			pr.printf("  %v", vn.Name)
+			pr.directives(vn.Directives)
+			pr.printf("\n")
+		}
+		pr.printf("}\n")
+	case ScalarDefNode:
+		pr.description(dn.Description, "")
+		pr.printf("scalar %v", dn.Name)
+		pr.directives(dn.Directives)
+		pr.printf("\n")
+	case UnionDefNode:
+		pr.description(dn.Description, "")
+		pr.printf("union %v", dn.Name)
+		pr.directives(dn.Directives)
+		pr.printf(" = %v\n", strings.Join(dn.Types, " | "))
+	case SchemaNode:
+		pr.description(dn.Description, "")
+		pr.printf("schema")
+		pr.directives(dn.Directives)
+		pr.fields(dn.Fields)
+	case DirectiveDefNode:
+		pr.description(dn.Description, "")
+		pr.printf("directive @%v", dn.Name)
+		pr.params(dn.Params)
+		if dn.Repeatable {
+			pr.printf(" repeatable")
+		}
+		pr.printf(" on %v\n", strings.Join(dn.Targets, " | "))
+	}
+}
+
+// PrintValue formats a value node as a GraphQL literal.
+func PrintValue(n Node) string {
+	var pr printer
+	pr.value(n)
+	return pr.b.String()
+}
+
+// PrintType formats a type reference such as [String!]!.
+func PrintType(n Node) string {
+	var pr printer
+	pr.typeRef(n)
+	return pr.b.String()
+}
+
+// PrintDirective formats an applied directive such as @auth(role: "admin").
+func PrintDirective(n Node) string {
+	var pr printer
+	pr.directives([]Node{n})
+	return strings.TrimPrefix(pr.b.String(), " ")
+}
+
+// Print formats a parsed schema back into SDL.
+func Print(n Node) string {
+	var pr printer
+	if dn, ok := n.(DocumentNode); ok {
+		for i, d := range dn.Definitions {
+			if i > 0 {
+				pr.printf("\n")
+			}
+			pr.definition(d)
+		}
+	} else {
+		pr.definition(n)
+	}
+	return pr.b.String()
+}
@@ -0,0 +1,28 @@
+package parse_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+func TestPrint(t *testing.T) {
+	tests := []string{
+		"sdl.graphqls",
+		"values.graphqls",
+	}
+
+	for _, name := range tests {
+		t.Run(name, func(t *testing.T) {
+			schema := parse.TestGetDoc(t, name)
+
+			ast := parse.TestParse(t, schema)
+
+			if diff := cmp.Diff(schema, parse.Print(ast)); diff != "" {
+				t.Fatalf("mismatch (expected, got) %v", diff)
+			}
+		})
+	}
+}
@@ -24,13 +24,20 @@ const (
 	WhitespaceToken
 	LeftParenToken
 	RightParenToken
-	CommaToken
 	BangToken
 	AtToken
 	LeftBracketToken
 	RightBracketToken
 	BarToken
 	EOFToken
+	StringToken
+	IntToken
+	FloatToken
+	EqualsToken
+	AmpToken
+	DollarToken
+	SpreadToken
+	CommentToken
 )
 
 func (tt TokenType) String() string {
@@ -49,8 +56,6 @@ func (tt TokenType) String() string {
 		return "left paren"
 	case RightParenToken:
 		return "right paren"
-	case CommaToken:
-		return "comma"
 	case BangToken:
 		return "bang"
 	case AtToken:
@@ -63,11 +68,36 @@ func (tt TokenType) String() string {
 		return "bar"
 	case EOFToken:
 		return "end of file"
+	case StringToken:
+		return "string"
+	case IntToken:
+		return "int"
+	case FloatToken:
+		return "float"
+	case EqualsToken:
+		return "equals"
+	case AmpToken:
+		return "ampersand"
+	case DollarToken:
+		return "dollar"
+	case SpreadToken:
+		return "spread"
+	case CommentToken:
+		return "comment"
+	case WhitespaceToken:
+		return "whitespace"
 	default:
 		return "unknown"
 	}
 }
 
+// Ignored reports whether tokens of this type are insignificant to the
+// grammar: white space, line terminators, commas, byte order marks and
+// comments.
+func (tt TokenType) Ignored() bool {
+	return tt == WhitespaceToken || tt == CommentToken
+}
+
 type Token struct {
 	TokenType TokenType
 	Loc       Loc
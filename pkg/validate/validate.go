@@ -0,0 +1,334 @@
+package validate
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/introspection"
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+)
+
+type Error struct {
+	Loc     parse.Loc
+	Message string
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("%v: %v", e.Loc, e.Message)
+}
+
+var BuiltinScalars = []string{"String", "Int", "Float", "Boolean", "ID"}
+
+// BuiltinDirectives are the directives that may be used without a
+// definition: the ones from the GraphQL specification, the AppSync
+// authorization directives and the @resolve, @goModel and @goField
+// directives read by the generators.
+var BuiltinDirectives = map[string][]string{
+	"deprecated":             {"FIELD_DEFINITION", "ENUM_VALUE", "ARGUMENT_DEFINITION", "INPUT_FIELD_DEFINITION"},
+	"specifiedBy":            {"SCALAR"},
+	"resolve":                {"FIELD_DEFINITION"},
+	"goModel":                {"OBJECT", "INPUT_OBJECT", "SCALAR", "ENUM", "UNION", "INTERFACE"},
+	"goField":                {"FIELD_DEFINITION", "INPUT_FIELD_DEFINITION", "ARGUMENT_DEFINITION"},
+	"aws_api_key":            {"OBJECT", "FIELD_DEFINITION"},
+	"aws_iam":                {"OBJECT", "FIELD_DEFINITION"},
+	"aws_oidc":               {"OBJECT", "FIELD_DEFINITION"},
+	"aws_lambda":             {"OBJECT", "FIELD_DEFINITION"},
+	"aws_cognito_user_pools": {"OBJECT", "FIELD_DEFINITION"},
+	"aws_auth":               {"FIELD_DEFINITION"},
+	"aws_subscribe":          {"FIELD_DEFINITION"},
+}
+
+var directiveLocations = map[string]bool{
+	"QUERY":                  true,
+	"MUTATION":               true,
+	"SUBSCRIPTION":           true,
+	"FIELD":                  true,
+	"FRAGMENT_DEFINITION":    true,
+	"FRAGMENT_SPREAD":        true,
+	"INLINE_FRAGMENT":        true,
+	"VARIABLE_DEFINITION":    true,
+	"SCHEMA":                 true,
+	"SCALAR":                 true,
+	"OBJECT":                 true,
+	"FIELD_DEFINITION":       true,
+	"ARGUMENT_DEFINITION":    true,
+	"INTERFACE":              true,
+	"UNION":                  true,
+	"ENUM":                   true,
+	"ENUM_VALUE":             true,
+	"INPUT_OBJECT":           true,
+	"INPUT_FIELD_DEFINITION": true,
+}
+
+type validator struct {
+	types      map[string]parse.Node
+	directives map[string][]string
+	errs       []Error
+}
+
+func (v *validator) report(n parse.Node, format string, args ...interface{}) {
+	v.errs = append(v.errs, Error{n.Loc(), fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) isScalar(name string) bool {
+	for _, s := range BuiltinScalars {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isAWSScalar reports whether name is a scalar that AppSync provides, which
+// schemas use without a definition like the AppSync directives. A schema
+// may still define them, for tools other than AppSync.
+func isAWSScalar(name string) bool {
+	for _, s := range introspection.AWSScalars {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+func typeName(n parse.Node) string {
+	switch dn := n.(type) {
+	case parse.TypeDefNode:
+		return dn.Name
+	case parse.EnumDefNode:
+		return dn.Name
+	case parse.ScalarDefNode:
+		return dn.Name
+	case parse.UnionDefNode:
+		return dn.Name
+	}
+	return ""
+}
+
+func (v *validator) collect(doc parse.DocumentNode) {
+	for _, n := range doc.Definitions {
+		if name := typeName(n); name != "" {
+			if _, ok := v.types[name]; ok || v.isScalar(name) {
+				v.report(n, "type %v is defined more than once", name)
+				continue
+			}
+			v.types[name] = n
+		}
+
+		switch dn := n.(type) {
+		case parse.DirectiveDefNode:
+			if _, ok := v.directives[dn.Name]; ok {
+				v.report(dn, "directive @%v is defined more than once", dn.Name)
+				continue
+			}
+			v.directives[dn.Name] = dn.Targets
+			for _, t := range dn.Targets {
+				if !directiveLocations[t] {
+					v.report(dn, "directive @%v has unknown location %v", dn.Name, t)
+				}
+			}
+		}
+	}
+	for name, targets := range BuiltinDirectives {
+		if _, ok := v.directives[name]; !ok {
+			v.directives[name] = targets
+		}
+	}
+}
+
+func (v *validator) checkType(n parse.Node, input bool, owner string) {
+	tn := n.(parse.TypeNode)
+	if v.isScalar(tn.Name) {
+		return
+	}
+	def, ok := v.types[tn.Name]
+	if !ok && isAWSScalar(tn.Name) {
+		return
+	}
+	if !ok {
+		v.report(tn, "%v has unknown type %v", owner, tn.Name)
+		return
+	}
+	if input && !isInput(def) {
+		v.report(tn, "%v must be an input type but %v is an output type", owner, tn.Name)
+	} else if !input && !isOutput(def) {
+		v.report(tn, "%v must be an output type but %v is an input type", owner, tn.Name)
+	}
+}
+
+func isInput(n parse.Node) bool {
+	switch dn := n.(type) {
+	case parse.TypeDefNode:
+		return dn.Input
+	case parse.UnionDefNode:
+		return false
+	}
+	return true
+}
+
+func isOutput(n parse.Node) bool {
+	if tdn, ok := n.(parse.TypeDefNode); ok {
+		return !tdn.Input
+	}
+	return true
+}
+
+func (v *validator) checkParams(params []parse.Node, owner string) {
+	names := make(map[string]bool)
+	for _, n := range params {
+		pn := n.(parse.ParamNode)
+		powner := "argument " + owner + "(" + pn.Name + ")"
+		if names[pn.Name] {
+			v.report(pn, "%v is defined more than once", powner)
+		}
+		names[pn.Name] = true
+		v.checkType(pn.Type, true, powner)
+		v.checkDirectives(pn.Directives, "ARGUMENT_DEFINITION", powner)
+	}
+}
+
+func (v *validator) checkDirectives(directives []parse.Node, location string, owner string) {
+	for _, n := range directives {
+		dn := n.(parse.DirectiveNode)
+		targets, ok := v.directives[dn.Name]
+		if !ok {
+			v.report(dn, "directive @%v used on %v is not defined", dn.Name, owner)
+			continue
+		}
+		allowed := false
+		for _, t := range targets {
+			if t == location {
+				allowed = true
+			}
+		}
+		if !allowed {
+			v.report(dn, "directive @%v is not allowed on %v (%v)", dn.Name, owner, location)
+		}
+	}
+}
+
+func (v *validator) checkTypeDef(tdn parse.TypeDefNode) {
+	location := "FIELD_DEFINITION"
+	switch {
+	case tdn.Input:
+		location = "INPUT_FIELD_DEFINITION"
+		v.checkDirectives(tdn.Directives, "INPUT_OBJECT", "type "+tdn.Name)
+	case tdn.Interface:
+		v.checkDirectives(tdn.Directives, "INTERFACE", "type "+tdn.Name)
+	default:
+		v.checkDirectives(tdn.Directives, "OBJECT", "type "+tdn.Name)
+	}
+
+	for _, name := range tdn.Interfaces {
+		if n, ok := v.types[name].(parse.TypeDefNode); !ok || !n.Interface {
+			v.report(tdn, "type %v implements %v which is not an interface", tdn.Name, name)
+		}
+	}
+
+	fields := make(map[string]bool)
+	for _, n := range tdn.Fields {
+		fn := n.(parse.FieldNode)
+		owner := "field " + tdn.Name + "." + fn.Name
+		if fields[fn.Name] {
+			v.report(fn, "%v is defined more than once", owner)
+		}
+		fields[fn.Name] = true
+
+		v.checkType(fn.Type, tdn.Input, owner)
+		v.checkDirectives(fn.Directives, location, owner)
+
+		if tdn.Input && len(fn.Params) > 0 {
+			v.report(fn, "%v is an input field and cannot have arguments", owner)
+		}
+		v.checkParams(fn.Params, tdn.Name+"."+fn.Name)
+	}
+}
+
+func (v *validator) checkEnum(edn parse.EnumDefNode) {
+	v.checkDirectives(edn.Directives, "ENUM", "type "+edn.Name)
+	values := make(map[string]bool)
+	for _, n := range edn.Values {
+		evn := n.(parse.EnumValueNode)
+		owner := "enum value " + edn.Name + "." + evn.Name
+		if values[evn.Name] {
+			v.report(evn, "%v is defined more than once", owner)
+		}
+		values[evn.Name] = true
+		v.checkDirectives(evn.Directives, "ENUM_VALUE", owner)
+	}
+}
+
+func (v *validator) checkUnion(udn parse.UnionDefNode) {
+	v.checkDirectives(udn.Directives, "UNION", "type "+udn.Name)
+	for _, name := range udn.Types {
+		if n, ok := v.types[name].(parse.TypeDefNode); !ok || n.Input || n.Interface {
+			v.report(udn, "union %v member %v must be an object type", udn.Name, name)
+		}
+	}
+}
+
+func (v *validator) checkSchema(sn parse.SchemaNode) {
+	operations := make(map[string]bool)
+	for _, n := range sn.Fields {
+		fn := n.(parse.FieldNode)
+		owner := "schema " + fn.Name
+		switch fn.Name {
+		case "query", "mutation", "subscription":
+		default:
+			v.report(fn, "%v is not an operation type", owner)
+		}
+		if operations[fn.Name] {
+			v.report(fn, "%v is defined more than once", owner)
+		}
+		operations[fn.Name] = true
+
+		tn := fn.Type.(parse.TypeNode)
+		if tdn, ok := v.types[tn.Name].(parse.TypeDefNode); !ok || tdn.Input || tdn.Interface {
+			v.report(tn, "%v must be an object type but got %v", owner, tn.Name)
+		}
+	}
+}
+
+func Validate(n parse.Node) []Error {
+	doc, ok := n.(parse.DocumentNode)
+	if !ok {
+		return []Error{{n.Loc(), "expected a document"}}
+	}
+
+	v := validator{
+		types:      make(map[string]parse.Node),
+		directives: make(map[string][]string),
+	}
+	v.collect(doc)
+
+	schemas := 0
+	for _, n := range doc.Definitions {
+		switch dn := n.(type) {
+		case parse.TypeDefNode:
+			v.checkTypeDef(dn)
+		case parse.EnumDefNode:
+			v.checkEnum(dn)
+		case parse.ScalarDefNode:
+			v.checkDirectives(dn.Directives, "SCALAR", "type "+dn.Name)
+		case parse.UnionDefNode:
+			v.checkUnion(dn)
+		case parse.DirectiveDefNode:
+			v.checkParams(dn.Params, "@"+dn.Name)
+		case parse.SchemaNode:
+			v.checkDirectives(dn.Directives, "SCHEMA", "schema")
+			if schemas++; schemas > 1 {
+				v.report(dn, "schema is defined more than once")
+			}
+			v.checkSchema(dn)
+		}
+	}
+
+	sort.SliceStable(v.errs, func(i, j int) bool {
+		a, b := v.errs[i].Loc, v.errs[j].Loc
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return v.errs
+}
@@ -0,0 +1,142 @@
+package validate_test
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/beauknowssoftware/go-gql-gen/pkg/parse"
+	"github.com/beauknowssoftware/go-gql-gen/pkg/validate"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		schema         string
+		expectedErrors []string
+	}{
+		"valid": {
+			schema: "type Query {\n  ping(a: Int!, b: PingInput): [String!]! @resolve\n}\n\ninput PingInput {\n  a: Int\n}\n\nschema {\n  query: Query\n}",
+		},
+		"unknown type": {
+			schema: "type Query {\n  ping: Pong\n}",
+			expectedErrors: []string{
+				"2,9: field Query.ping has unknown type Pong",
+			},
+		},
+		"aws scalars": {
+			schema: "type A @aws_iam {\n  at: AWSDateTime @aws_iam\n  on(day: AWSDate): [AWSJSON!]\n}",
+		},
+		"defined aws scalar": {
+			schema: "scalar AWSDateTime\n\ntype A {\n  at: AWSDateTime\n}",
+		},
+		"unknown param type": {
+			schema: "type Query {\n  ping(a: Pong): String\n}",
+			expectedErrors: []string{
+				"2,11: argument Query.ping(a) has unknown type Pong",
+			},
+		},
+		"duplicate type": {
+			schema: "type Query {\n  ping: String\n}\n\ninput Query {\n  ping: String\n}",
+			expectedErrors: []string{
+				"5,1: type Query is defined more than once",
+			},
+		},
+		"redefined scalar": {
+			schema: "type String {\n  ping: String\n}",
+			expectedErrors: []string{
+				"1,1: type String is defined more than once",
+			},
+		},
+		"duplicate field": {
+			schema: "type Query {\n  ping: String\n  ping: Int\n}",
+			expectedErrors: []string{
+				"3,3: field Query.ping is defined more than once",
+			},
+		},
+		"duplicate param": {
+			schema: "type Query {\n  ping(a: Int, a: String): String\n}",
+			expectedErrors: []string{
+				"2,16: argument Query.ping(a) is defined more than once",
+			},
+		},
+		"input as output": {
+			schema: "type Query {\n  ping: PingInput\n}\n\ninput PingInput {\n  a: Int\n}",
+			expectedErrors: []string{
+				"2,9: field Query.ping must be an output type but PingInput is an input type",
+			},
+		},
+		"output as input": {
+			schema: "type Query {\n  ping(a: Query): String\n}\n\ninput PingInput {\n  q: Query\n}",
+			expectedErrors: []string{
+				"2,11: argument Query.ping(a) must be an input type but Query is an output type",
+				"6,6: field PingInput.q must be an input type but Query is an output type",
+			},
+		},
+		"undefined directive": {
+			schema: "type Query {\n  ping: String @my_directive\n}",
+			expectedErrors: []string{
+				"2,16: directive @my_directive used on field Query.ping is not defined",
+			},
+		},
+		"defined directive": {
+			schema: "directive @my_directive on FIELD_DEFINITION | OBJECT\n\ntype Query {\n  ping: String @my_directive\n}",
+		},
+		"directive location": {
+			schema: "directive @my_directive on OBJECT\n\ninput PingInput {\n  ping: String @my_directive @resolve\n}",
+			expectedErrors: []string{
+				"4,16: directive @my_directive is not allowed on field PingInput.ping (INPUT_FIELD_DEFINITION)",
+				"4,30: directive @resolve is not allowed on field PingInput.ping (INPUT_FIELD_DEFINITION)",
+			},
+		},
+		"directive definitions": {
+			schema: "directive @a on SOMETHING\n\ndirective @a on OBJECT",
+			expectedErrors: []string{
+				"1,1: directive @a has unknown location SOMETHING",
+				"3,1: directive @a is defined more than once",
+			},
+		},
+		"schema": {
+			schema: "input PingInput {\n  a: Int\n}\n\nschema {\n  query: Query\n  mutation: PingInput\n  other: PingInput\n}",
+			expectedErrors: []string{
+				"6,10: schema query must be an object type but got Query",
+				"7,13: schema mutation must be an object type but got PingInput",
+				"8,3: schema other is not an operation type",
+				"8,10: schema other must be an object type but got PingInput",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ast := parse.TestParse(t, test.schema)
+
+			errs := make([]string, 0)
+			for _, err := range validate.Validate(ast) {
+				errs = append(errs, err.Error())
+			}
+
+			expectedErrors := test.expectedErrors
+			if expectedErrors == nil {
+				expectedErrors = []string{}
+			}
+			if diff := cmp.Diff(expectedErrors, errs); diff != "" {
+				t.Fatalf("mismatch (-expected,+got) %v", diff)
+			}
+		})
+	}
+}
+
+// TestValidateGoDirectives checks the schema the generators are tested with,
+// which uses @goModel and @goField without defining them.
+func TestValidateGoDirectives(t *testing.T) {
+	d, err := ioutil.ReadFile("../../internal/gqlgen/testdata/directives.graphqls")
+	if err != nil {
+		t.Fatalf("failed to read schema %v", err)
+	}
+	ast := parse.TestParse(t, string(d))
+
+	if errs := validate.Validate(ast); len(errs) > 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+}